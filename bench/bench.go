@@ -0,0 +1,199 @@
+// Package bench measures MSM, NTT, conversion and copy operations for any
+// curve and size range and compares the resulting reports.
+//
+// A Target sets up one operation at one size; Run measures every operation
+// of Ops at every size on each target. Two targets are provided: Host, on
+// gnark-crypto, for machines without a GPU, and Device, over any
+// iciclegnark.Curve.
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Operations measured by Run.
+const (
+	MsmG1          = "msm_g1"
+	MsmG2          = "msm_g2"
+	MsmG1Batch     = "msm_g1_batch" // BatchSize G1 MSMs of the same points
+	Ntt            = "ntt"
+	INtt           = "intt"
+	CosetNtt       = "coset_ntt"
+	ConvertScalars = "convert_scalars"
+	ConvertG1      = "convert_g1"
+	CopyScalars    = "copy_scalars"      // host to device, conversion included
+	CopyG1         = "copy_g1"           // host to device, conversion included
+	CopyScalarsOut = "copy_scalars_back" // device to host, conversion included
+
+	CopyScalarsStaged = "copy_scalars_staged" // CopyScalars through pinned host buffers
+	CopyG1Staged      = "copy_g1_staged"      // CopyG1 through pinned host buffers
+)
+
+// Ops lists every operation in report order.
+var Ops = []string{MsmG1, MsmG2, MsmG1Batch, Ntt, INtt, CosetNtt, ConvertScalars, ConvertG1, CopyScalars, CopyG1, CopyScalarsOut, CopyScalarsStaged, CopyG1Staged}
+
+// BatchSize is the number of MSMs in MsmG1Batch.
+const BatchSize = 4
+
+// ErrUnsupported is returned by Target.Setup for operations a target cannot
+// run; Run skips them.
+var ErrUnsupported = errors.New("bench: unsupported operation")
+
+// Case is one operation set up at one size.
+type Case struct {
+	Run func() error
+	// Elements processed by one run, used for throughput.
+	Elements int
+	// Bytes moved between host and device by one run, zero if none.
+	Bytes int64
+	// Close releases what Setup allocated; it may be nil.
+	Close func()
+}
+
+// Target sets up operations of one curve.
+type Target interface {
+	Curve() ecc.ID
+	Setup(op string, size int) (*Case, error)
+}
+
+// Config selects what Run measures.
+type Config struct {
+	Ops    []string
+	Sizes  []int
+	Runs   int // measured runs per case
+	Warmup int // unmeasured runs before them
+}
+
+// Sizes returns the powers of two from 2^minLog to 2^maxLog.
+func Sizes(minLog, maxLog int) []int {
+	var sizes []int
+	for l := minLog; l <= maxLog; l++ {
+		sizes = append(sizes, 1<<l)
+	}
+
+	return sizes
+}
+
+// Run measures every operation of cfg at every size on each target.
+func Run(backend string, targets []Target, cfg Config) (*Report, error) {
+	if cfg.Runs < 1 {
+		return nil, fmt.Errorf("bench: %d runs, want at least 1", cfg.Runs)
+	}
+
+	report := &Report{
+		Version:   Version,
+		Backend:   backend,
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Date:      time.Now().UTC().Truncate(time.Second),
+	}
+
+	for _, t := range targets {
+		for _, op := range cfg.Ops {
+			for _, size := range cfg.Sizes {
+				c, err := t.Setup(op, size)
+				if errors.Is(err, ErrUnsupported) {
+					continue
+				}
+				if err != nil {
+					return nil, fmt.Errorf("bench: %s %s 2^%d: %w", t.Curve(), op, log2(size), err)
+				}
+
+				samples, err := measure(c, cfg.Runs, cfg.Warmup)
+				if c.Close != nil {
+					c.Close()
+				}
+				if err != nil {
+					return nil, fmt.Errorf("bench: %s %s 2^%d: %w", t.Curve(), op, log2(size), err)
+				}
+
+				report.Results = append(report.Results, summarize(t.Curve(), op, size, c, samples))
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func measure(c *Case, runs, warmup int) ([]time.Duration, error) {
+	for i := 0; i < warmup; i++ {
+		if err := c.Run(); err != nil {
+			return nil, err
+		}
+	}
+
+	samples := make([]time.Duration, runs)
+	for i := range samples {
+		start := time.Now()
+		if err := c.Run(); err != nil {
+			return nil, err
+		}
+		samples[i] = time.Since(start)
+	}
+
+	return samples, nil
+}
+
+func summarize(curve ecc.ID, op string, size int, c *Case, samples []time.Duration) Result {
+	sorted := append([]time.Duration{}, samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, s := range sorted {
+		total += s
+	}
+
+	r := Result{
+		Curve: curve.String(),
+		Op:    op,
+		Size:  size,
+		Runs:  len(sorted),
+		Min:   sorted[0],
+		Mean:  total / time.Duration(len(sorted)),
+		P50:   Percentile(sorted, 50),
+		P90:   Percentile(sorted, 90),
+		P99:   Percentile(sorted, 99),
+		Max:   sorted[len(sorted)-1],
+	}
+
+	if seconds := r.P50.Seconds(); seconds > 0 {
+		r.ElementsPerSecond = float64(c.Elements) / seconds
+		r.BytesPerSecond = float64(c.Bytes) / seconds
+	}
+
+	return r
+}
+
+// Percentile returns the nearest-rank p-th percentile of sorted samples.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+
+	return sorted[rank]
+}
+
+func log2(n int) int {
+	l := 0
+	for ; n > 1; n >>= 1 {
+		l++
+	}
+
+	return l
+}
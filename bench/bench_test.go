@@ -0,0 +1,197 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ingonyama-zk/iciclegnark/faults"
+	"github.com/ingonyama-zk/iciclegnark/internal/hostcurve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 10; i++ {
+		sorted = append(sorted, time.Duration(i))
+	}
+
+	assert.Equal(t, time.Duration(5), Percentile(sorted, 50))
+	assert.Equal(t, time.Duration(9), Percentile(sorted, 90))
+	assert.Equal(t, time.Duration(10), Percentile(sorted, 99))
+	assert.Equal(t, time.Duration(1), Percentile(sorted, 0))
+	assert.Zero(t, Percentile(nil, 50))
+}
+
+func TestSizes(t *testing.T) {
+	assert.Equal(t, []int{4, 8, 16}, Sizes(2, 4))
+	assert.Empty(t, Sizes(4, 2))
+}
+
+func TestRunHost(t *testing.T) {
+	for _, id := range []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761} {
+		target, err := Host(id)
+		assert.NoError(t, err)
+
+		report, err := Run("cpu", []Target{target}, Config{Ops: Ops, Sizes: Sizes(2, 3), Runs: 3, Warmup: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, "cpu", report.Backend)
+		// the host has no pinned staging
+		assert.Len(t, report.Results, (len(Ops)-2)*2)
+
+		for _, r := range report.Results {
+			assert.Equal(t, id.String(), r.Curve)
+			assert.Equal(t, 3, r.Runs)
+			assert.True(t, r.Min <= r.P50 && r.P50 <= r.P90 && r.P90 <= r.P99 && r.P99 <= r.Max, r.Op)
+			if r.Op == MsmG1Batch {
+				assert.InDelta(t, float64(BatchSize*r.Size)/r.P50.Seconds(), r.ElementsPerSecond, 1, r.Op)
+			}
+			if r.Op == CopyScalars {
+				assert.NotZero(t, r.BytesPerSecond)
+			}
+		}
+	}
+
+	_, err := Host(ecc.SECP256K1)
+	assert.Error(t, err)
+}
+
+type target struct {
+	closed int
+}
+
+func (t *target) Curve() ecc.ID { return ecc.BN254 }
+
+func (t *target) Setup(op string, size int) (*Case, error) {
+	switch op {
+	case MsmG1:
+		return &Case{Elements: size, Run: func() error { return nil }, Close: func() { t.closed++ }}, nil
+	case Ntt:
+		return &Case{Elements: size, Run: func() error { return errors.New("failed") }}, nil
+	}
+
+	return nil, ErrUnsupported
+}
+
+func TestRunSkipsAndFails(t *testing.T) {
+	tg := &target{}
+	report, err := Run("fake", []Target{tg}, Config{Ops: []string{MsmG1, MsmG2}, Sizes: []int{1, 2}, Runs: 1})
+	assert.NoError(t, err)
+	assert.Len(t, report.Results, 2)
+	assert.Equal(t, 2, tg.closed)
+
+	_, err = Run("fake", []Target{tg}, Config{Ops: []string{Ntt}, Sizes: []int{4}, Runs: 1})
+	assert.ErrorContains(t, err, "bn254 ntt 2^2")
+
+	_, err = Run("fake", []Target{tg}, Config{Ops: Ops, Sizes: []int{1}})
+	assert.Error(t, err)
+}
+
+func testReport() *Report {
+	return &Report{
+		Version: Version,
+		Backend: "cpu",
+		Date:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Results: []Result{
+			{Curve: "bn254", Op: MsmG1, Size: 1024, Runs: 2, Min: 90, Mean: 100, P50: 100, P90: 110, P99: 110, Max: 110, ElementsPerSecond: 1.024e10},
+			{Curve: "bn254", Op: Ntt, Size: 1024, Runs: 2, Min: 40, Mean: 50, P50: 50, P90: 60, P99: 60, Max: 60, ElementsPerSecond: 2.048e10},
+			{Curve: "bn254", Op: CopyScalars, Size: 1024, Runs: 2, Min: 10, Mean: 10, P50: 10, P90: 10, P99: 10, Max: 10, ElementsPerSecond: 1.024e11, BytesPerSecond: 3.2768e12},
+		},
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, testReport().WriteJSON(&buf))
+	assert.Contains(t, buf.String(), `"p50_ns": 100`)
+
+	report, err := Read(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, testReport(), report)
+
+	_, err = Read(bytes.NewBufferString(`{"version": 2}`))
+	assert.Error(t, err)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, testReport().WriteCSV(&buf))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 4)
+	assert.Equal(t, csvHeader, rows[0])
+	assert.Equal(t, []string{"bn254", "msm_g1", "1024", "2", "90", "100", "100", "110", "110", "110", "10240000000.0", "0.0"}, rows[1])
+}
+
+func TestCompare(t *testing.T) {
+	base, current := testReport(), testReport()
+	current.Results[0].P50 = 120 // 20% slower
+	current.Results[1].P50 = 40  // faster
+	current.Results = current.Results[:2]
+
+	changes := Compare(base, current, 0.1)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, Change{Curve: "bn254", Op: MsmG1, Size: 1024, Base: 100, Current: 120, Ratio: 1.2, Regression: true}, changes[0])
+	assert.False(t, changes[1].Regression)
+	assert.Equal(t, 0.8, changes[1].Ratio)
+
+	assert.False(t, Compare(base, current, 0.25)[0].Regression)
+}
+
+func TestDevice(t *testing.T) {
+	c := hostcurve.NewBN254()
+	converted := 0
+	target, err := Device[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac](c, Conversions[fr.Element, bn254.G1Affine]{
+		Scalars:       func(scalars []fr.Element) { converted += len(scalars) },
+		StagedScalars: c.CopyScalarsToDevice,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ecc.BN254, target.Curve())
+
+	report, err := Run("host", []Target{target}, Config{Ops: Ops, Sizes: []int{4}, Runs: 2})
+	assert.NoError(t, err)
+
+	// without G1 conversions, convert_g1 and copy_g1_staged are skipped
+	var ops []string
+	for _, r := range report.Results {
+		ops = append(ops, r.Op)
+	}
+	assert.NotContains(t, ops, ConvertG1)
+	assert.NotContains(t, ops, CopyG1Staged)
+	assert.Contains(t, ops, CopyScalarsStaged)
+	assert.Len(t, ops, len(Ops)-2)
+	assert.Equal(t, 8, converted)
+	assert.Zero(t, c.Live())
+}
+
+func TestDeviceFaults(t *testing.T) {
+	c := faults.Wrap[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac](hostcurve.NewBN254())
+	target, err := Device[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac](c, Conversions[fr.Element, bn254.G1Affine]{})
+	assert.NoError(t, err)
+
+	// a failed allocation frees the buffers of the case
+	c.Inject(faults.FailAllocation(3))
+	_, err = target.Setup(CosetNtt, 8)
+	assert.ErrorIs(t, err, faults.ErrInjected)
+	assert.Zero(t, c.Live())
+
+	// a failed kernel fails the run
+	c.Inject(faults.FailKernel(faults.NttOnDevice, 2))
+	cs, err := target.Setup(Ntt, 8)
+	assert.NoError(t, err)
+	assert.ErrorIs(t, cs.Run(), faults.ErrInjected)
+	cs.Close()
+	assert.Zero(t, c.Live())
+	assert.Zero(t, c.BadFrees())
+}
+
+func TestDeviceTypes(t *testing.T) {
+	_, err := Device[int, int, int, int, int](hostcurve.NewInts(ecc.BN254), Conversions[int, int]{})
+	assert.ErrorContains(t, err, "does not match")
+}
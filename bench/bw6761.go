@@ -0,0 +1,58 @@
+package bench
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr/fft"
+)
+
+func init() {
+	hosts[ecc.BW6_761] = &host[fr.Element, bw6761.G1Affine, bw6761.G2Affine, *fr.Element, *bw6761.G1Affine, *bw6761.G2Affine]{
+		id: ecc.BW6_761,
+		g1Base: func(s *big.Int) (p bw6761.G1Affine) {
+			p.ScalarMultiplicationBase(s)
+			return
+		},
+		g2Base: func(s *big.Int) (p bw6761.G2Affine) {
+			p.ScalarMultiplicationBase(s)
+			return
+		},
+		msmG1: func(points []bw6761.G1Affine, scalars []fr.Element) error {
+			var p bw6761.G1Affine
+			_, err := p.MultiExp(points, scalars, ecc.MultiExpConfig{})
+			return err
+		},
+		msmG2: func(points []bw6761.G2Affine, scalars []fr.Element) error {
+			var p bw6761.G2Affine
+			_, err := p.MultiExp(points, scalars, ecc.MultiExpConfig{})
+			return err
+		},
+		ntt: func(n int) func(values []fr.Element, inverse, coset bool) {
+			domain := fft.NewDomain(uint64(n))
+			return func(values []fr.Element, inverse, coset bool) {
+				var opts []fft.Option
+				if coset {
+					opts = append(opts, fft.OnCoset())
+				}
+				if inverse {
+					domain.FFTInverse(values, fft.DIF, opts...)
+				} else {
+					domain.FFT(values, fft.DIF, opts...)
+				}
+				fft.BitReverse(values)
+			}
+		},
+		cosetPowers: func(n int) []fr.Element {
+			g := fft.NewDomain(uint64(n)).FrMultiplicativeGen
+			powers := make([]fr.Element, n)
+			powers[0].SetOne()
+			for i := 1; i < n; i++ {
+				powers[i].Mul(&powers[i-1], &g)
+			}
+			return powers
+		},
+	}
+}
@@ -0,0 +1,214 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark"
+)
+
+// Conversions are the host conversions and staged uploads of a curve package,
+// which iciclegnark.Curve does not expose. Nil functions leave the matching
+// operation unsupported.
+type Conversions[Fr, G1Affine any] struct {
+	Scalars func(scalars []Fr)
+	G1      func(points []G1Affine)
+
+	// Uploads through pinned host buffers, as CopyToDeviceStaged and
+	// CopyPointsToDeviceStaged; the caller frees the result.
+	StagedScalars func(scalars []Fr) (unsafe.Pointer, error)
+	StagedG1      func(points []G1Affine) (unsafe.Pointer, error)
+}
+
+type device[Fr, G1Affine, G1Jac, G2Affine, G2Jac any] struct {
+	curve       iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]
+	inputs      inputs[Fr, G1Affine, G2Affine]
+	conversions Conversions[Fr, G1Affine]
+}
+
+// Device returns the target running on c. Inputs are drawn as for Host, so
+// the curve must be one Host supports.
+func Device[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], conversions Conversions[Fr, G1Affine]) (Target, error) {
+	h, ok := hosts[c.ID()]
+	if !ok {
+		return nil, fmt.Errorf("bench: unsupported curve %s", c.ID())
+	}
+
+	in, ok := h.(inputs[Fr, G1Affine, G2Affine])
+	if !ok {
+		return nil, fmt.Errorf("bench: curve %s does not match the gnark-crypto types", c.ID())
+	}
+
+	return &device[Fr, G1Affine, G1Jac, G2Affine, G2Jac]{curve: c, inputs: in, conversions: conversions}, nil
+}
+
+func (d *device[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) Curve() ecc.ID { return d.curve.ID() }
+
+func (d *device[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) Setup(op string, size int) (*Case, error) {
+	c := d.curve
+	cs := &Case{Elements: size}
+
+	// buffers are freed by Close, or on the first failed allocation
+	var buffers []unsafe.Pointer
+	var setupErr error
+	keep := func(p unsafe.Pointer, err error) unsafe.Pointer {
+		if err != nil {
+			setupErr = errors.Join(setupErr, err)
+			return nil
+		}
+		buffers = append(buffers, p)
+		return p
+	}
+	cs.Close = func() {
+		for _, p := range buffers {
+			c.FreeDevicePointer(p)
+		}
+	}
+
+	switch op {
+	case MsmG1:
+		scalars_d := keep(c.CopyScalarsToDevice(d.inputs.scalars(size)))
+		points_d := keep(c.CopyG1PointsToDevice(d.inputs.g1(size)))
+		cs.Run = func() error {
+			_, err := c.MsmOnDevice(scalars_d, points_d, size)
+			return err
+		}
+	case MsmG2:
+		scalars_d := keep(c.CopyScalarsToDevice(d.inputs.scalars(size)))
+		points_d := keep(c.CopyG2PointsToDevice(d.inputs.g2(size)))
+		cs.Run = func() error {
+			_, err := c.MsmG2OnDevice(scalars_d, points_d, size)
+			return err
+		}
+	case MsmG1Batch:
+		points_d := keep(c.CopyG1PointsToDevice(d.inputs.g1(size)))
+		all := d.inputs.scalars(BatchSize * size)
+		batch := make([]unsafe.Pointer, BatchSize)
+		for i := range batch {
+			batch[i] = keep(c.CopyScalarsToDevice(all[i*size : (i+1)*size]))
+		}
+		cs.Elements = BatchSize * size
+		cs.Run = func() error {
+			for _, scalars_d := range batch {
+				if _, err := c.MsmOnDevice(scalars_d, points_d, size); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case Ntt, CosetNtt:
+		if size&(size-1) != 0 {
+			return nil, ErrUnsupported
+		}
+		twiddles_d := keep(c.GenerateTwiddleFactors(size, false))
+		scalars_d := keep(c.CopyScalarsToDevice(d.inputs.scalars(size)))
+		out_d := keep(c.CopyScalarsToDevice(make([]Fr, size)))
+		var cosetPowers_d unsafe.Pointer
+		if op == CosetNtt {
+			cosetPowers_d = keep(c.CopyScalarsToDevice(d.inputs.powers(size)))
+		}
+		cs.Run = func() error {
+			return c.NttOnDevice(out_d, scalars_d, twiddles_d, cosetPowers_d, size, size, op == CosetNtt)
+		}
+	case INtt:
+		if size&(size-1) != 0 {
+			return nil, ErrUnsupported
+		}
+		twiddles_d := keep(c.GenerateTwiddleFactors(size, true))
+		scalars_d := keep(c.CopyScalarsToDevice(d.inputs.scalars(size)))
+		cs.Run = func() error {
+			out_d, err := c.INttOnDevice(scalars_d, twiddles_d, nil, size, false)
+			if err != nil {
+				return err
+			}
+			c.FreeDevicePointer(out_d)
+			return nil
+		}
+	case ConvertScalars:
+		if d.conversions.Scalars == nil {
+			return nil, ErrUnsupported
+		}
+		scalars := d.inputs.scalars(size)
+		cs.Run = func() error {
+			d.conversions.Scalars(scalars)
+			return nil
+		}
+	case ConvertG1:
+		if d.conversions.G1 == nil {
+			return nil, ErrUnsupported
+		}
+		points := d.inputs.g1(size)
+		cs.Run = func() error {
+			d.conversions.G1(points)
+			return nil
+		}
+	case CopyScalars:
+		scalars := d.inputs.scalars(size)
+		cs.Bytes = int64(size * d.inputs.scalarBytes())
+		cs.Run = func() error {
+			scalars_d, err := c.CopyScalarsToDevice(scalars)
+			if err != nil {
+				return err
+			}
+			c.FreeDevicePointer(scalars_d)
+			return nil
+		}
+	case CopyG1:
+		points := d.inputs.g1(size)
+		cs.Bytes = int64(size * d.inputs.g1Bytes())
+		cs.Run = func() error {
+			points_d, err := c.CopyG1PointsToDevice(points)
+			if err != nil {
+				return err
+			}
+			c.FreeDevicePointer(points_d)
+			return nil
+		}
+	case CopyScalarsStaged:
+		if d.conversions.StagedScalars == nil {
+			return nil, ErrUnsupported
+		}
+		scalars := d.inputs.scalars(size)
+		cs.Bytes = int64(size * d.inputs.scalarBytes())
+		cs.Run = func() error {
+			scalars_d, err := d.conversions.StagedScalars(scalars)
+			if err != nil {
+				return err
+			}
+			c.FreeDevicePointer(scalars_d)
+			return nil
+		}
+	case CopyG1Staged:
+		if d.conversions.StagedG1 == nil {
+			return nil, ErrUnsupported
+		}
+		points := d.inputs.g1(size)
+		cs.Bytes = int64(size * d.inputs.g1Bytes())
+		cs.Run = func() error {
+			points_d, err := d.conversions.StagedG1(points)
+			if err != nil {
+				return err
+			}
+			c.FreeDevicePointer(points_d)
+			return nil
+		}
+	case CopyScalarsOut:
+		scalars_d := keep(c.CopyScalarsToDevice(d.inputs.scalars(size)))
+		cs.Bytes = int64(size * d.inputs.scalarBytes())
+		cs.Run = func() error {
+			_, err := c.CopyScalarsFromDevice(scalars_d, size)
+			return err
+		}
+	default:
+		return nil, ErrUnsupported
+	}
+
+	if setupErr != nil {
+		cs.Close()
+		return nil, setupErr
+	}
+
+	return cs, nil
+}
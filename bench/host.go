@@ -0,0 +1,202 @@
+package bench
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// distinctPoints bounds the number of distinct random points drawn per
+// input; larger inputs repeat them, which does not change MSM cost.
+const distinctPoints = 256
+
+type scalar[T any] interface {
+	*T
+	Marshal() []byte
+	SetBytesCanonical(e []byte) error
+}
+
+type point[T any] interface {
+	*T
+	Marshal() []byte
+}
+
+// inputs draws the random inputs of a curve, shared by the host and device
+// targets.
+type inputs[Fr, G1Affine, G2Affine any] interface {
+	scalars(n int) []Fr
+	g1(n int) []G1Affine
+	g2(n int) []G2Affine
+	// powers returns g^i for i < n, g the multiplicative generator.
+	powers(n int) []Fr
+	scalarBytes() int
+	g1Bytes() int
+}
+
+// host implements Target on gnark-crypto from the few operations that differ
+// between its curve packages. Conversions are to the canonical encoding and
+// copies are host memory copies, a baseline for the device numbers.
+type host[Fr, G1Affine, G2Affine any, PFr scalar[Fr], PG1 point[G1Affine], PG2 point[G2Affine]] struct {
+	id     ecc.ID
+	g1Base func(s *big.Int) G1Affine
+	g2Base func(s *big.Int) G2Affine
+	msmG1  func(points []G1Affine, scalars []Fr) error
+	msmG2  func(points []G2Affine, scalars []Fr) error
+	// ntt returns the NTT over a domain of size n, transforming values in
+	// place, natural order in and out.
+	ntt         func(n int) func(values []Fr, inverse, coset bool)
+	cosetPowers func(n int) []Fr
+}
+
+var hosts = make(map[ecc.ID]Target)
+
+// Host returns the gnark-crypto target of curve.
+func Host(curve ecc.ID) (Target, error) {
+	t, ok := hosts[curve]
+	if !ok {
+		return nil, fmt.Errorf("bench: unsupported curve %s", curve)
+	}
+
+	return t, nil
+}
+
+func (h *host[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) Curve() ecc.ID { return h.id }
+
+func (h *host[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) scalars(n int) []Fr {
+	rng := rand.New(rand.NewSource(int64(n)))
+	modulus := h.id.ScalarField()
+	buf := make([]byte, h.scalarBytes())
+
+	res := make([]Fr, n)
+	for i := range res {
+		s := new(big.Int).Rand(rng, modulus)
+		if err := PFr(&res[i]).SetBytesCanonical(s.FillBytes(buf)); err != nil {
+			panic(err)
+		}
+	}
+
+	return res
+}
+
+func (h *host[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) g1(n int) []G1Affine {
+	return repeat(n, h.g1Base, h.id.ScalarField())
+}
+
+func (h *host[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) g2(n int) []G2Affine {
+	return repeat(n, h.g2Base, h.id.ScalarField())
+}
+
+func (h *host[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) powers(n int) []Fr {
+	return h.cosetPowers(n)
+}
+
+func (h *host[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) scalarBytes() int {
+	return len(PFr(new(Fr)).Marshal())
+}
+
+func (h *host[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) g1Bytes() int {
+	return len(PG1(new(G1Affine)).Marshal())
+}
+
+func repeat[T any](n int, base func(s *big.Int) T, modulus *big.Int) []T {
+	rng := rand.New(rand.NewSource(int64(n)))
+	distinct := n
+	if distinct > distinctPoints {
+		distinct = distinctPoints
+	}
+
+	res := make([]T, n)
+	for i := 0; i < distinct; i++ {
+		res[i] = base(new(big.Int).Rand(rng, modulus))
+	}
+	for i := distinct; i < n; i++ {
+		res[i] = res[i%distinct]
+	}
+
+	return res
+}
+
+func (h *host[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) Setup(op string, size int) (*Case, error) {
+	c := &Case{Elements: size}
+
+	switch op {
+	case MsmG1:
+		points, scalars := h.g1(size), h.scalars(size)
+		c.Run = func() error { return h.msmG1(points, scalars) }
+	case MsmG2:
+		points, scalars := h.g2(size), h.scalars(size)
+		c.Run = func() error { return h.msmG2(points, scalars) }
+	case MsmG1Batch:
+		points, all := h.g1(size), h.scalars(BatchSize*size)
+		batch := make([][]Fr, BatchSize)
+		for i := range batch {
+			batch[i] = all[i*size : (i+1)*size]
+		}
+		c.Elements = BatchSize * size
+		c.Run = func() error {
+			for _, scalars := range batch {
+				if err := h.msmG1(points, scalars); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case Ntt, INtt, CosetNtt:
+		if size&(size-1) != 0 {
+			return nil, ErrUnsupported
+		}
+		values, ntt := h.scalars(size), h.ntt(size)
+		c.Run = func() error {
+			ntt(values, op == INtt, op == CosetNtt)
+			return nil
+		}
+	case ConvertScalars:
+		scalars := h.scalars(size)
+		dst := make([]byte, size*h.scalarBytes())
+		c.Run = func() error {
+			convert[Fr, PFr](dst, scalars)
+			return nil
+		}
+	case ConvertG1:
+		points := h.g1(size)
+		dst := make([]byte, size*h.g1Bytes())
+		c.Run = func() error {
+			convert[G1Affine, PG1](dst, points)
+			return nil
+		}
+	case CopyScalars, CopyScalarsOut:
+		src, dst := h.scalars(size), make([]Fr, size)
+		c.Bytes = int64(size * h.scalarBytes())
+		c.Run = func() error {
+			copy(dst, src)
+			return nil
+		}
+	case CopyG1:
+		src, dst := h.g1(size), make([]G1Affine, size)
+		c.Bytes = int64(size * h.g1Bytes())
+		c.Run = func() error {
+			copy(dst, src)
+			return nil
+		}
+	default:
+		return nil, ErrUnsupported
+	}
+
+	return c, nil
+}
+
+func convert[T any, PT interface {
+	*T
+	Marshal() []byte
+}](dst []byte, values []T) {
+	if len(values) == 0 {
+		return
+	}
+
+	n := len(dst) / len(values)
+	for i := range values {
+		copy(dst[i*n:], PT(&values[i]).Marshal())
+	}
+}
@@ -0,0 +1,149 @@
+package bench
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Version is the report format version written by Run and accepted by Read.
+const Version = 1
+
+// Report is the outcome of Run. Durations are encoded in nanoseconds.
+type Report struct {
+	Version   int       `json:"version"`
+	Backend   string    `json:"backend"`
+	GoVersion string    `json:"go_version"`
+	OS        string    `json:"os"`
+	Arch      string    `json:"arch"`
+	Date      time.Time `json:"date"`
+	Results   []Result  `json:"results"`
+}
+
+// Result summarizes the runs of one operation at one size. Throughput is
+// computed from the median.
+type Result struct {
+	Curve             string        `json:"curve"`
+	Op                string        `json:"op"`
+	Size              int           `json:"size"`
+	Runs              int           `json:"runs"`
+	Min               time.Duration `json:"min_ns"`
+	Mean              time.Duration `json:"mean_ns"`
+	P50               time.Duration `json:"p50_ns"`
+	P90               time.Duration `json:"p90_ns"`
+	P99               time.Duration `json:"p99_ns"`
+	Max               time.Duration `json:"max_ns"`
+	ElementsPerSecond float64       `json:"elements_per_second"`
+	BytesPerSecond    float64       `json:"bytes_per_second,omitempty"`
+}
+
+// WriteJSON encodes r as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+var csvHeader = []string{"curve", "op", "size", "runs", "min_ns", "mean_ns", "p50_ns", "p90_ns", "p99_ns", "max_ns", "elements_per_second", "bytes_per_second"}
+
+// WriteCSV writes one row per result, with a header.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, res := range r.Results {
+		row := []string{res.Curve, res.Op, strconv.Itoa(res.Size), strconv.Itoa(res.Runs)}
+		for _, d := range []time.Duration{res.Min, res.Mean, res.P50, res.P90, res.P99, res.Max} {
+			row = append(row, strconv.FormatInt(d.Nanoseconds(), 10))
+		}
+		row = append(row,
+			strconv.FormatFloat(res.ElementsPerSecond, 'f', 1, 64),
+			strconv.FormatFloat(res.BytesPerSecond, 'f', 1, 64))
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// Read decodes a JSON report.
+func Read(r io.Reader) (*Report, error) {
+	var report Report
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("bench: %w", err)
+	}
+	if report.Version != Version {
+		return nil, fmt.Errorf("bench: report version %d, want %d", report.Version, Version)
+	}
+
+	return &report, nil
+}
+
+// Load reads the JSON report at path.
+func Load(path string) (*Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Change compares the median of one result in two reports.
+type Change struct {
+	Curve      string
+	Op         string
+	Size       int
+	Base       time.Duration
+	Current    time.Duration
+	Ratio      float64 // Current / Base
+	Regression bool
+}
+
+// Compare matches the results of current with those of base by curve,
+// operation and size and flags as regressions the medians more than
+// threshold slower, 0.1 meaning 10%. Results missing from either report are
+// ignored. Changes follow the order of base.
+func Compare(base, current *Report, threshold float64) []Change {
+	type key struct {
+		curve, op string
+		size      int
+	}
+
+	medians := make(map[key]time.Duration, len(current.Results))
+	for _, r := range current.Results {
+		medians[key{r.Curve, r.Op, r.Size}] = r.P50
+	}
+
+	var changes []Change
+	for _, r := range base.Results {
+		cur, ok := medians[key{r.Curve, r.Op, r.Size}]
+		if !ok || r.P50 <= 0 {
+			continue
+		}
+
+		ratio := float64(cur) / float64(r.P50)
+		changes = append(changes, Change{
+			Curve:      r.Curve,
+			Op:         r.Op,
+			Size:       r.Size,
+			Base:       r.P50,
+			Current:    cur,
+			Ratio:      ratio,
+			Regression: ratio > 1+threshold,
+		})
+	}
+
+	return changes
+}
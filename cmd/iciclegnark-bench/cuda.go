@@ -0,0 +1,112 @@
+//go:build !cpu
+
+package main
+
+import (
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	gnarkbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12377fr "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	gnarkbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	bls12381fr "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	gnarkbn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	gnarkbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	bw6761fr "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	iciclebls12377 "github.com/ingonyama-zk/icicle/goicicle/curves/bls12377"
+	iciclebls12381 "github.com/ingonyama-zk/icicle/goicicle/curves/bls12381"
+	iciclebn254 "github.com/ingonyama-zk/icicle/goicicle/curves/bn254"
+	iciclebw6761 "github.com/ingonyama-zk/icicle/goicicle/curves/bw6761"
+	"github.com/ingonyama-zk/iciclegnark/bench"
+	"github.com/ingonyama-zk/iciclegnark/curves/bls12377"
+	"github.com/ingonyama-zk/iciclegnark/curves/bls12381"
+	"github.com/ingonyama-zk/iciclegnark/curves/bn254"
+	"github.com/ingonyama-zk/iciclegnark/curves/bw6761"
+	"github.com/ingonyama-zk/iciclegnark/hostmem"
+)
+
+func init() {
+	backends["cuda"] = cuda
+	defaultBackend = "cuda"
+}
+
+// Staging ring of the staged copies, the size the curve package benchmarks
+// use. It lives as long as the command.
+const (
+	ringSlots      = 4
+	ringChunkBytes = 8 << 20
+)
+
+func cuda(id ecc.ID) (bench.Target, error) {
+	ring, err := hostmem.NewRing(ringSlots, ringChunkBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch id {
+	case ecc.BN254:
+		return bench.Device[bn254fr.Element, gnarkbn254.G1Affine, gnarkbn254.G1Jac, gnarkbn254.G2Affine, gnarkbn254.G2Jac](bn254.Curve{}, bench.Conversions[bn254fr.Element, gnarkbn254.G1Affine]{
+			Scalars: func(s []bn254fr.Element) {
+				bn254.BatchConvertFromFrGnarkInto(make([]iciclebn254.G1ScalarField, len(s)), s)
+			},
+			G1: func(p []gnarkbn254.G1Affine) {
+				bn254.BatchConvertFromG1AffineInto(make([]iciclebn254.G1PointAffine, len(p)), p)
+			},
+			StagedScalars: func(s []bn254fr.Element) (unsafe.Pointer, error) {
+				return bn254.CopyToDeviceStaged(s, ring)
+			},
+			StagedG1: func(p []gnarkbn254.G1Affine) (unsafe.Pointer, error) {
+				return bn254.CopyPointsToDeviceStaged(p, ring)
+			},
+		})
+	case ecc.BLS12_377:
+		return bench.Device[bls12377fr.Element, gnarkbls12377.G1Affine, gnarkbls12377.G1Jac, gnarkbls12377.G2Affine, gnarkbls12377.G2Jac](bls12377.Curve{}, bench.Conversions[bls12377fr.Element, gnarkbls12377.G1Affine]{
+			Scalars: func(s []bls12377fr.Element) {
+				bls12377.BatchConvertFromFrGnarkInto(make([]iciclebls12377.G1ScalarField, len(s)), s)
+			},
+			G1: func(p []gnarkbls12377.G1Affine) {
+				bls12377.BatchConvertFromG1AffineInto(make([]iciclebls12377.G1PointAffine, len(p)), p)
+			},
+			StagedScalars: func(s []bls12377fr.Element) (unsafe.Pointer, error) {
+				return bls12377.CopyToDeviceStaged(s, ring)
+			},
+			StagedG1: func(p []gnarkbls12377.G1Affine) (unsafe.Pointer, error) {
+				return bls12377.CopyPointsToDeviceStaged(p, ring)
+			},
+		})
+	case ecc.BLS12_381:
+		return bench.Device[bls12381fr.Element, gnarkbls12381.G1Affine, gnarkbls12381.G1Jac, gnarkbls12381.G2Affine, gnarkbls12381.G2Jac](bls12381.Curve{}, bench.Conversions[bls12381fr.Element, gnarkbls12381.G1Affine]{
+			Scalars: func(s []bls12381fr.Element) {
+				bls12381.BatchConvertFromFrGnarkInto(make([]iciclebls12381.G1ScalarField, len(s)), s)
+			},
+			G1: func(p []gnarkbls12381.G1Affine) {
+				bls12381.BatchConvertFromG1AffineInto(make([]iciclebls12381.G1PointAffine, len(p)), p)
+			},
+			StagedScalars: func(s []bls12381fr.Element) (unsafe.Pointer, error) {
+				return bls12381.CopyToDeviceStaged(s, ring)
+			},
+			StagedG1: func(p []gnarkbls12381.G1Affine) (unsafe.Pointer, error) {
+				return bls12381.CopyPointsToDeviceStaged(p, ring)
+			},
+		})
+	case ecc.BW6_761:
+		return bench.Device[bw6761fr.Element, gnarkbw6761.G1Affine, gnarkbw6761.G1Jac, gnarkbw6761.G2Affine, gnarkbw6761.G2Jac](bw6761.Curve{}, bench.Conversions[bw6761fr.Element, gnarkbw6761.G1Affine]{
+			Scalars: func(s []bw6761fr.Element) {
+				bw6761.BatchConvertFromFrGnarkInto(make([]iciclebw6761.G1ScalarField, len(s)), s)
+			},
+			G1: func(p []gnarkbw6761.G1Affine) {
+				bw6761.BatchConvertFromG1AffineInto(make([]iciclebw6761.G1PointAffine, len(p)), p)
+			},
+			StagedScalars: func(s []bw6761fr.Element) (unsafe.Pointer, error) {
+				return bw6761.CopyToDeviceStaged(s, ring)
+			},
+			StagedG1: func(p []gnarkbw6761.G1Affine) (unsafe.Pointer, error) {
+				return bw6761.CopyPointsToDeviceStaged(p, ring)
+			},
+		})
+	}
+	ring.Free()
+
+	return bench.Host(id)
+}
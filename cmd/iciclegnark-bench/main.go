@@ -0,0 +1,163 @@
+// Command iciclegnark-bench measures MSM, NTT, conversion and copy operations
+// and writes a JSON or CSV report, or compares two JSON reports.
+//
+//	iciclegnark-bench -curve bn254 -min-log 10 -max-log 20 -out report.json
+//	iciclegnark-bench compare -threshold 0.1 base.json report.json
+//
+// copy_scalars and copy_g1 upload from pageable Go memory, copy_scalars_staged
+// and copy_g1_staged through a ring of pinned buffers; running them together
+// compares the two:
+//
+//	iciclegnark-bench -curve bn254 -ops copy_g1,copy_g1_staged -min-log 16 -max-log 24
+//
+// The cuda backend runs on the curve packages under curves/. Built with
+// -tags cpu, the command has only the cpu backend, on gnark-crypto, and needs
+// neither CUDA nor a GPU.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark/bench"
+)
+
+// backends returns the target of a curve for each backend name.
+var backends = map[string]func(ecc.ID) (bench.Target, error){
+	"cpu": bench.Host,
+}
+
+var defaultBackend = "cpu"
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) > 1 && os.Args[1] == "compare" {
+		os.Exit(compare(os.Args[2:]))
+	}
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	backend := flag.String("backend", defaultBackend, "backend: "+strings.Join(names, ", "))
+	curve := flag.String("curve", "all", "curve name as in gnark-crypto, or all")
+	ops := flag.String("ops", strings.Join(bench.Ops, ","), "comma separated operations")
+	minLog := flag.Int("min-log", 10, "log2 of the smallest size")
+	maxLog := flag.Int("max-log", 16, "log2 of the largest size")
+	runs := flag.Int("runs", 10, "measured runs per operation and size")
+	warmup := flag.Int("warmup", 2, "unmeasured runs before them")
+	format := flag.String("format", "json", "report format: json or csv")
+	out := flag.String("out", "", "report file, standard output if empty")
+	flag.Parse()
+
+	target, ok := backends[*backend]
+	if !ok {
+		log.Fatalf("unknown backend %q, want one of %s", *backend, strings.Join(names, ", "))
+	}
+
+	ids := []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761}
+	if *curve != "all" {
+		id, err := ecc.IDFromString(*curve)
+		if err != nil {
+			log.Fatalf("curve %q: %v", *curve, err)
+		}
+		ids = []ecc.ID{id}
+	}
+
+	var targets []bench.Target
+	for _, id := range ids {
+		t, err := target(id)
+		if err != nil {
+			log.Fatal(err)
+		}
+		targets = append(targets, t)
+	}
+
+	report, err := bench.Run(*backend, targets, bench.Config{
+		Ops:    strings.Split(*ops, ","),
+		Sizes:  bench.Sizes(*minLog, *maxLog),
+		Runs:   *runs,
+		Warmup: *warmup,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "json":
+		err = report.WriteJSON(w)
+	case "csv":
+		err = report.WriteCSV(w)
+	default:
+		err = fmt.Errorf("unknown format %q, want json or csv", *format)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// compare prints the median changes between two reports and returns 1 if any
+// is a regression.
+func compare(args []string) int {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	threshold := fs.Float64("threshold", 0.1, "slowdown of the median flagged as a regression, 0.1 is 10%")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: iciclegnark-bench compare [-threshold t] base.json current.json")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	base, err := bench.Load(fs.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	current, err := bench.Load(fs.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	regressions := 0
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "curve\top\tsize\tbase\tcurrent\tchange\t")
+	for _, c := range bench.Compare(base, current, *threshold) {
+		mark := ""
+		if c.Regression {
+			mark = "REGRESSION"
+			regressions++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%+.1f%%\t%s\n", c.Curve, c.Op, c.Size, c.Base, c.Current, (c.Ratio-1)*100, mark)
+	}
+	tw.Flush()
+
+	if regressions > 0 {
+		fmt.Printf("%d regressions over %.0f%%\n", regressions, *threshold*100)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,40 @@
+//go:build !cpu
+
+package main
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	gnarkbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	bls12377fr "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	gnarkbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	bls12381fr "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	gnarkbn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	gnarkbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	bw6761fr "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/ingonyama-zk/iciclegnark/curves/bls12377"
+	"github.com/ingonyama-zk/iciclegnark/curves/bls12381"
+	"github.com/ingonyama-zk/iciclegnark/curves/bn254"
+	"github.com/ingonyama-zk/iciclegnark/curves/bw6761"
+	"github.com/ingonyama-zk/iciclegnark/server"
+)
+
+func init() {
+	backends["cuda"] = cuda
+	defaultBackend = "cuda"
+}
+
+func cuda(id ecc.ID) (server.Engine, error) {
+	switch id {
+	case ecc.BN254:
+		return server.Device[bn254fr.Element, gnarkbn254.G1Affine, gnarkbn254.G1Jac, gnarkbn254.G2Affine, gnarkbn254.G2Jac](bn254.Curve{})
+	case ecc.BLS12_377:
+		return server.Device[bls12377fr.Element, gnarkbls12377.G1Affine, gnarkbls12377.G1Jac, gnarkbls12377.G2Affine, gnarkbls12377.G2Jac](bls12377.Curve{})
+	case ecc.BLS12_381:
+		return server.Device[bls12381fr.Element, gnarkbls12381.G1Affine, gnarkbls12381.G1Jac, gnarkbls12381.G2Affine, gnarkbls12381.G2Jac](bls12381.Curve{})
+	case ecc.BW6_761:
+		return server.Device[bw6761fr.Element, gnarkbw6761.G1Affine, gnarkbw6761.G1Jac, gnarkbw6761.G2Affine, gnarkbw6761.G2Jac](bw6761.Curve{})
+	}
+
+	return server.Host(id)
+}
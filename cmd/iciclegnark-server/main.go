@@ -0,0 +1,223 @@
+// Command iciclegnark-server serves MSMs, NTTs, KZG commitments and Groth16
+// proofs over HTTP/JSON, see package server.
+//
+//	iciclegnark-server -addr localhost:8547 -curves bn254 -key kzg=srs.icicle
+//	iciclegnark-server -curves bn254 -groth16 transfer=bn254:transfer.r1cs:transfer.pk
+//
+// Each -key loads the pk.g1 section of an SRS file written by
+// iciclegnark-srs under its name, kept resident for the process lifetime, and
+// registers the Commitment circuit of the same name. Each -groth16 registers
+// the Groth16 circuit of a gnark constraint system and proving key, written
+// with WriteTo, proven by gnark on the host. Built with -tags cpu,
+// the command has only the cpu backend, on gnark-crypto, and needs neither
+// CUDA nor a GPU.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/ingonyama-zk/iciclegnark"
+	"github.com/ingonyama-zk/iciclegnark/server"
+	"github.com/ingonyama-zk/iciclegnark/srs"
+)
+
+// backends returns the engine of a curve for each backend name.
+var backends = map[string]func(ecc.ID) (server.Engine, error){
+	"cpu": server.Host,
+}
+
+var defaultBackend = "cpu"
+
+// keys collects the repeated -key flags.
+type keys map[string]string
+
+func (k keys) String() string { return fmt.Sprint(map[string]string(k)) }
+
+func (k keys) Set(v string) error {
+	name, path, ok := strings.Cut(v, "=")
+	if !ok || name == "" || path == "" {
+		return fmt.Errorf("want name=path, got %q", v)
+	}
+	if _, ok := k[name]; ok {
+		return fmt.Errorf("key %s given twice", name)
+	}
+	k[name] = path
+
+	return nil
+}
+
+func main() {
+	log.SetFlags(0)
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	srsKeys := keys{}
+	addr := flag.String("addr", "localhost:8547", "listen address")
+	backend := flag.String("backend", defaultBackend, "backend: "+strings.Join(names, ", "))
+	curve := flag.String("curves", "all", "comma separated curve names as in gnark-crypto, or all")
+	flag.Var(srsKeys, "key", "name=path of an SRS file to load as a key and circuit, repeatable")
+	maxRequest := flag.Int64("max-request", server.DefaultMaxRequestBytes, "limit of request bodies, in bytes")
+	groth16Keys := keys{}
+	flag.Var(groth16Keys, "groth16", "name=curve:cs:pk of a Groth16 circuit, repeatable")
+	flag.Parse()
+
+	engine, ok := backends[*backend]
+	if !ok {
+		log.Fatalf("unknown backend %q, want one of %s", *backend, strings.Join(names, ", "))
+	}
+
+	ids := []ecc.ID{ecc.BN254, ecc.BLS12_377, ecc.BLS12_381, ecc.BW6_761}
+	if *curve != "all" {
+		ids = nil
+		for _, name := range strings.Split(*curve, ",") {
+			id, err := ecc.IDFromString(name)
+			if err != nil {
+				log.Fatalf("curve %q: %v", name, err)
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	var engines []server.Engine
+	for _, id := range ids {
+		e, err := engine(id)
+		if err != nil {
+			log.Fatal(err)
+		}
+		engines = append(engines, e)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	s, err := server.New(engines, iciclegnark.WithLogger(logger))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer s.Close()
+	s.SetMaxRequestBytes(*maxRequest)
+
+	for name, path := range srsKeys {
+		c, n, err := commitment(name, path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := s.Register(name, c); err != nil {
+			log.Fatal(err)
+		}
+		logger.Info("loaded key", slog.String("name", name), slog.String("curve", c.ID.String()), slog.Int("points", n))
+	}
+	for name, spec := range groth16Keys {
+		c, err := groth16Circuit(spec)
+		if err != nil {
+			log.Fatalf("circuit %s: %v", name, err)
+		}
+		if err := s.Register(name, c); err != nil {
+			log.Fatal(err)
+		}
+		logger.Info("loaded circuit", slog.String("name", name), slog.String("curve", c.Curve().String()), slog.Int("constraints", c.CS.GetNbConstraints()))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	hs := &http.Server{Addr: *addr, Handler: s}
+	go func() {
+		<-ctx.Done()
+		hs.Shutdown(context.Background())
+	}()
+
+	logger.Info("serving", slog.String("addr", *addr), slog.String("backend", *backend))
+	if err := hs.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+// commitment reads the proving key of an SRS file into a Commitment circuit
+// and returns its number of points.
+func commitment(name, path string) (*server.Commitment, int, error) {
+	f, err := srs.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	id, err := f.ID()
+	if err != nil {
+		return nil, 0, err
+	}
+	raw, err := f.Section("pk.g1")
+	if err != nil {
+		return nil, 0, err
+	}
+	size, err := srs.PointBytes(id, srs.G1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	n := len(raw) / size
+	var points bytes.Buffer
+	for i := 0; i < n; i++ {
+		p, err := srs.Point(id, srs.G1, raw, i)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %w", path, err)
+		}
+		points.Write(p)
+	}
+
+	return &server.Commitment{ID: id, Key: name, Points: points.Bytes()}, n, nil
+}
+
+// groth16Circuit reads the constraint system and proving key of spec,
+// curve:cs:pk.
+func groth16Circuit(spec string) (*server.Groth16, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("want curve:cs:pk, got %q", spec)
+	}
+	id, err := ecc.IDFromString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("curve %q: %w", parts[0], err)
+	}
+
+	c := &server.Groth16{CS: groth16.NewCS(id), Key: groth16.NewProvingKey(id)}
+	if err := readFile(parts[1], c.CS); err != nil {
+		return nil, err
+	}
+	if err := readFile(parts[2], c.Key); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func readFile(path string, r io.ReaderFrom) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := r.ReadFrom(bufio.NewReader(f)); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+//go:build !cpu
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	gnarkbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	kzgbls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/kzg"
+	gnarkbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	kzgbls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	gnarkbn254 "github.com/consensys/gnark-crypto/ecc/bn254"
+	kzgbn254 "github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	gnarkbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	kzgbw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761/kzg"
+	groth16bls12377 "github.com/consensys/gnark/backend/groth16/bls12-377"
+	groth16bls12381 "github.com/consensys/gnark/backend/groth16/bls12-381"
+	groth16bn254 "github.com/consensys/gnark/backend/groth16/bn254"
+	groth16bw6761 "github.com/consensys/gnark/backend/groth16/bw6-761"
+	"github.com/ingonyama-zk/iciclegnark/curves/bls12377"
+	"github.com/ingonyama-zk/iciclegnark/curves/bls12381"
+	"github.com/ingonyama-zk/iciclegnark/curves/bn254"
+	"github.com/ingonyama-zk/iciclegnark/curves/bw6761"
+	"github.com/ingonyama-zk/iciclegnark/srs"
+)
+
+func init() {
+	convert = convertFile
+}
+
+// readers decode each source kind into converted sections.
+var readers = map[string]func(id ecc.ID, r io.Reader) ([]srs.Data, error){
+	"kzg":     readKZG,
+	"groth16": readGroth16,
+}
+
+func convertFile(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	curve := fs.String("curve", "bn254", "curve name as in gnark-crypto")
+	source := fs.String("source", "kzg", "kind of input: kzg or groth16")
+	in := fs.String("in", "", "serialized kzg.SRS or groth16.ProvingKey, as written by WriteTo or WriteRawTo")
+	out := fs.String("out", "", "device-ready output file")
+	fs.Parse(args)
+
+	if *in == "" || *out == "" {
+		usage()
+	}
+	read, ok := readers[*source]
+	if !ok {
+		return fmt.Errorf("unknown source %q, want kzg or groth16", *source)
+	}
+
+	id, err := ecc.IDFromString(*curve)
+	if err != nil {
+		return fmt.Errorf("curve %q: %w", *curve, err)
+	}
+
+	r, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	sections, err := read(id, bufio.NewReader(r))
+	if err != nil {
+		return err
+	}
+
+	w, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+
+	m, err := srs.Write(bw, id, *source, sections)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, s := range m.Sections {
+		fmt.Printf("%s: %d %s points, sha256 %s\n", s.Name, s.Count, s.Group, s.SHA256)
+	}
+
+	return nil
+}
+
+// readKZG decodes a kzg.SRS, which checks its points are in the subgroup, and
+// converts and verifies its sections.
+func readKZG(id ecc.ID, r io.Reader) ([]srs.Data, error) {
+	switch id {
+	case ecc.BN254:
+		var s kzgbn254.SRS
+		if _, err := s.ReadFrom(r); err != nil {
+			return nil, err
+		}
+		var c collector
+		c.add(convertSection(id, "pk.g1", srs.G1, s.Pk.G1, bn254.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "vk.g1", srs.G1, []gnarkbn254.G1Affine{s.Vk.G1}, bn254.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "vk.g2", srs.G2, s.Vk.G2[:], bn254.BatchConvertFromG2Affine))
+		return c.data, c.err
+	case ecc.BLS12_377:
+		var s kzgbls12377.SRS
+		if _, err := s.ReadFrom(r); err != nil {
+			return nil, err
+		}
+		var c collector
+		c.add(convertSection(id, "pk.g1", srs.G1, s.Pk.G1, bls12377.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "vk.g1", srs.G1, []gnarkbls12377.G1Affine{s.Vk.G1}, bls12377.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "vk.g2", srs.G2, s.Vk.G2[:], bls12377.BatchConvertFromG2Affine))
+		return c.data, c.err
+	case ecc.BLS12_381:
+		var s kzgbls12381.SRS
+		if _, err := s.ReadFrom(r); err != nil {
+			return nil, err
+		}
+		var c collector
+		c.add(convertSection(id, "pk.g1", srs.G1, s.Pk.G1, bls12381.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "vk.g1", srs.G1, []gnarkbls12381.G1Affine{s.Vk.G1}, bls12381.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "vk.g2", srs.G2, s.Vk.G2[:], bls12381.BatchConvertFromG2Affine))
+		return c.data, c.err
+	case ecc.BW6_761:
+		var s kzgbw6761.SRS
+		if _, err := s.ReadFrom(r); err != nil {
+			return nil, err
+		}
+		var c collector
+		c.add(convertSection(id, "pk.g1", srs.G1, s.Pk.G1, bw6761.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "vk.g1", srs.G1, []gnarkbw6761.G1Affine{s.Vk.G1}, bw6761.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "vk.g2", srs.G2, s.Vk.G2[:], bw6761.BatchConvertFromG2Affine))
+		return c.data, c.err
+	}
+
+	return nil, fmt.Errorf("unsupported curve %s", id)
+}
+
+// readGroth16 decodes a groth16.ProvingKey of gnark, which checks its points
+// are in the subgroup, and converts and verifies the G1 and G2 points the
+// prover's MSMs use. The Pedersen commitment keys are not converted.
+func readGroth16(id ecc.ID, r io.Reader) ([]srs.Data, error) {
+	switch id {
+	case ecc.BN254:
+		var pk groth16bn254.ProvingKey
+		if _, err := pk.ReadFrom(r); err != nil {
+			return nil, err
+		}
+		var c collector
+		c.add(convertSection(id, "pk.g1.abd", srs.G1, []gnarkbn254.G1Affine{pk.G1.Alpha, pk.G1.Beta, pk.G1.Delta}, bn254.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.a", srs.G1, pk.G1.A, bn254.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.b", srs.G1, pk.G1.B, bn254.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.z", srs.G1, pk.G1.Z, bn254.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.k", srs.G1, pk.G1.K, bn254.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g2.bd", srs.G2, []gnarkbn254.G2Affine{pk.G2.Beta, pk.G2.Delta}, bn254.BatchConvertFromG2Affine))
+		c.add(convertSection(id, "pk.g2.b", srs.G2, pk.G2.B, bn254.BatchConvertFromG2Affine))
+		return c.data, c.err
+	case ecc.BLS12_377:
+		var pk groth16bls12377.ProvingKey
+		if _, err := pk.ReadFrom(r); err != nil {
+			return nil, err
+		}
+		var c collector
+		c.add(convertSection(id, "pk.g1.abd", srs.G1, []gnarkbls12377.G1Affine{pk.G1.Alpha, pk.G1.Beta, pk.G1.Delta}, bls12377.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.a", srs.G1, pk.G1.A, bls12377.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.b", srs.G1, pk.G1.B, bls12377.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.z", srs.G1, pk.G1.Z, bls12377.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.k", srs.G1, pk.G1.K, bls12377.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g2.bd", srs.G2, []gnarkbls12377.G2Affine{pk.G2.Beta, pk.G2.Delta}, bls12377.BatchConvertFromG2Affine))
+		c.add(convertSection(id, "pk.g2.b", srs.G2, pk.G2.B, bls12377.BatchConvertFromG2Affine))
+		return c.data, c.err
+	case ecc.BLS12_381:
+		var pk groth16bls12381.ProvingKey
+		if _, err := pk.ReadFrom(r); err != nil {
+			return nil, err
+		}
+		var c collector
+		c.add(convertSection(id, "pk.g1.abd", srs.G1, []gnarkbls12381.G1Affine{pk.G1.Alpha, pk.G1.Beta, pk.G1.Delta}, bls12381.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.a", srs.G1, pk.G1.A, bls12381.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.b", srs.G1, pk.G1.B, bls12381.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.z", srs.G1, pk.G1.Z, bls12381.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.k", srs.G1, pk.G1.K, bls12381.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g2.bd", srs.G2, []gnarkbls12381.G2Affine{pk.G2.Beta, pk.G2.Delta}, bls12381.BatchConvertFromG2Affine))
+		c.add(convertSection(id, "pk.g2.b", srs.G2, pk.G2.B, bls12381.BatchConvertFromG2Affine))
+		return c.data, c.err
+	case ecc.BW6_761:
+		var pk groth16bw6761.ProvingKey
+		if _, err := pk.ReadFrom(r); err != nil {
+			return nil, err
+		}
+		var c collector
+		c.add(convertSection(id, "pk.g1.abd", srs.G1, []gnarkbw6761.G1Affine{pk.G1.Alpha, pk.G1.Beta, pk.G1.Delta}, bw6761.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.a", srs.G1, pk.G1.A, bw6761.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.b", srs.G1, pk.G1.B, bw6761.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.z", srs.G1, pk.G1.Z, bw6761.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g1.k", srs.G1, pk.G1.K, bw6761.BatchConvertFromG1Affine))
+		c.add(convertSection(id, "pk.g2.bd", srs.G2, []gnarkbw6761.G2Affine{pk.G2.Beta, pk.G2.Delta}, bw6761.BatchConvertFromG2Affine))
+		c.add(convertSection(id, "pk.g2.b", srs.G2, pk.G2.B, bw6761.BatchConvertFromG2Affine))
+		return c.data, c.err
+	}
+
+	return nil, fmt.Errorf("unsupported curve %s", id)
+}
+
+// convertSection converts points with batch, a BatchConvertFrom function of
+// a curve package, and checks the result decodes back to points.
+func convertSection[T any, PT interface {
+	*T
+	Marshal() []byte
+}, D any](id ecc.ID, name, group string, points []T, batch func([]T) []D) (srs.Data, error) {
+	raw := srs.Bytes(batch(points))
+	if err := srs.Verify[T, PT](id, group, raw, points); err != nil {
+		return srs.Data{}, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return srs.Data{Name: name, Group: group, Points: raw}, nil
+}
+
+// collector gathers sections until the first error.
+type collector struct {
+	data []srs.Data
+	err  error
+}
+
+func (c *collector) add(d srs.Data, err error) {
+	if c.err != nil {
+		return
+	}
+	if err != nil {
+		c.err = err
+		return
+	}
+	c.data = append(c.data, d)
+}
@@ -0,0 +1,97 @@
+// Command iciclegnark-srs prepares gnark-crypto SRS files for the device and
+// inspects the result.
+//
+//	iciclegnark-srs convert -curve bn254 -in srs.kzg -out srs.icicle
+//	iciclegnark-srs convert -curve bn254 -source groth16 -in circuit.pk -out pk.icicle
+//	iciclegnark-srs inspect -samples 16 srs.icicle
+//
+// convert reads a serialized kzg.SRS of gnark-crypto or groth16.ProvingKey of
+// gnark, converts its G1 and G2 points to the icicle layout with the curve
+// packages, checks every converted point against its source and writes a
+// file in the format of package srs. The Pedersen commitment keys of a
+// proving key are left out. It needs the
+// CUDA build of the curve packages; built with -tags cpu only inspect is
+// available.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ingonyama-zk/iciclegnark/srs"
+)
+
+// convert is set by the CUDA build.
+var convert func(args []string) error
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "convert":
+		if convert == nil {
+			log.Fatal("convert needs the CUDA build of iciclegnark-srs")
+		}
+		err = convert(os.Args[2:])
+	case "inspect":
+		err = inspect(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: iciclegnark-srs convert -curve curve [-source kzg|groth16] -in file -out file")
+	fmt.Fprintln(os.Stderr, "       iciclegnark-srs inspect [-samples n] file")
+	os.Exit(2)
+}
+
+// inspect prints the manifest of a file and spot-checks each section.
+func inspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	samples := fs.Int("samples", 16, "points decoded and checked per section")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		usage()
+	}
+
+	f, err := srs.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Printf("curve %s, source %s, format version %d\n\n", f.Curve, f.Source, f.Version)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "section\tgroup\tpoints\tbytes\tsha256\tcheck\t")
+	failed := 0
+	for _, s := range f.Sections {
+		status := "ok"
+		if err := f.SpotCheck(s.Name, *samples); err != nil {
+			status = err.Error()
+			failed++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%s\t\n", s.Name, s.Group, s.Count, s.Length, s.SHA256, status)
+	}
+	tw.Flush()
+
+	if failed > 0 {
+		return fmt.Errorf("%d sections failed their checks", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Command testvectors writes deterministic test vector files, one
+// <out>/<curve>.json per curve, in the format of package testvectors.
+//
+//	go run ./cmd/testvectors -curve bn254 -seed 1 -size 16 -out testvectors/testdata
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark/testvectors"
+)
+
+func main() {
+	curve := flag.String("curve", "all", "curve name as in gnark-crypto, or all")
+	seed := flag.Int64("seed", 1, "seed of the random inputs")
+	size := flag.Int("size", 16, "number of scalars and points")
+	out := flag.String("out", ".", "output directory")
+	flag.Parse()
+
+	ids := testvectors.Curves()
+	if *curve != "all" {
+		id, err := ecc.IDFromString(*curve)
+		if err != nil {
+			log.Fatalf("curve %q: %v", *curve, err)
+		}
+		ids = []ecc.ID{id}
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	for _, id := range ids {
+		if err := write(id, *seed, *size, *out); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func write(id ecc.ID, seed int64, size int, out string) error {
+	v, err := testvectors.Generate(id, seed, size)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(out, fmt.Sprintf("%s.json", id))
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := v.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,328 @@
+// Package conformance runs the same table of checks against any iciclegnark.Curve,
+// comparing device results with a host Reference built on gnark-crypto.
+//
+// Each curve package runs it from its tests:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac](t, Curve{}, reference{}, conformance.ConfigFromEnv())
+//	}
+package conformance
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"testing"
+	"unsafe"
+
+	"github.com/ingonyama-zk/iciclegnark"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Reference computes on the host what a Curve computes on device.
+type Reference[Fr, G1Affine, G1Jac, G2Affine, G2Jac any] interface {
+	RandomScalars(n int) []Fr
+	RandomG1Points(n int) []G1Affine
+	RandomG2Points(n int) []G2Affine
+
+	MultiExpG1(points []G1Affine, scalars []Fr) G1Jac
+	MultiExpG2(points []G2Affine, scalars []Fr) G2Jac
+	EqualG1(a, b G1Jac) bool
+	EqualG2(a, b G2Jac) bool
+
+	// FFT and FFTInverse work in place, natural order in and out, on the
+	// coset shifted by the multiplicative generator when coset is set
+	FFT(values []Fr, coset bool)
+	FFTInverse(values []Fr, coset bool)
+	// CosetPowers returns g^i, or g^-i when inverse is set, for i < n
+	CosetPowers(n int, inverse bool) []Fr
+	// PolyOps sets a[i] = (a[i]*b[i] - c[i]) * den[i]
+	PolyOps(a, b, c, den []Fr)
+
+	// Conversions to the icicle layout and back, on the host
+	ScalarRoundTrip(s Fr) Fr
+	G1RoundTrip(p G1Affine) G1Affine
+	G2RoundTrip(p G2Affine) G2Affine
+}
+
+// Config bounds the sizes used by the checks.
+type Config struct {
+	MaxLogMSM   int
+	MaxLogG2MSM int
+	MaxLogNTT   int
+	Conversions int // number of random elements converted in the round trip checks
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxLogMSM:   10,
+		MaxLogG2MSM: 8,
+		MaxLogNTT:   12,
+		Conversions: 64,
+	}
+}
+
+// ConfigFromEnv is DefaultConfig overridden by ICICLEGNARK_MAX_LOG_MSM,
+// ICICLEGNARK_MAX_LOG_G2_MSM, ICICLEGNARK_MAX_LOG_NTT and ICICLEGNARK_CONVERSIONS.
+func ConfigFromEnv() Config {
+	config := DefaultConfig()
+
+	for name, v := range map[string]*int{
+		"ICICLEGNARK_MAX_LOG_MSM":    &config.MaxLogMSM,
+		"ICICLEGNARK_MAX_LOG_G2_MSM": &config.MaxLogG2MSM,
+		"ICICLEGNARK_MAX_LOG_NTT":    &config.MaxLogNTT,
+		"ICICLEGNARK_CONVERSIONS":    &config.Conversions,
+	} {
+		if s, ok := os.LookupEnv(name); ok {
+			if n, err := strconv.Atoi(s); err == nil {
+				*v = n
+			}
+		}
+	}
+
+	return config
+}
+
+// Sizes returns the MSM sizes checked up to 2^maxLog: 1, 2, 3 and 2^k-1, 2^k, 2^k+1.
+func Sizes(maxLog int) []int {
+	seen := map[int]bool{}
+	var sizes []int
+	add := func(n int) {
+		if n > 0 && n <= 1<<maxLog && !seen[n] {
+			seen[n] = true
+			sizes = append(sizes, n)
+		}
+	}
+
+	add(1)
+	add(2)
+	add(3)
+	for k := 2; k <= maxLog; k++ {
+		add(1<<k - 1)
+		add(1 << k)
+		add(1<<k + 1)
+	}
+	sort.Ints(sizes)
+
+	return sizes
+}
+
+type check struct {
+	name string
+	run  func(t *testing.T)
+}
+
+// Run executes every check as a subtest of t.
+func Run[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](t *testing.T, curve iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], ref Reference[Fr, G1Affine, G1Jac, G2Affine, G2Jac], config Config) {
+	h := harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]{curve: curve, ref: ref, config: config}
+
+	checks := []check{
+		{"ScalarRoundTrip", h.scalarRoundTrip},
+		{"G1RoundTrip", h.g1RoundTrip},
+		{"G2RoundTrip", h.g2RoundTrip},
+		{"DeviceScalarRoundTrip", h.deviceScalarRoundTrip},
+	}
+
+	for _, size := range Sizes(config.MaxLogMSM) {
+		checks = append(checks, check{fmt.Sprintf("MSM/%d", size), h.msm(size)})
+	}
+	for _, size := range Sizes(config.MaxLogG2MSM) {
+		checks = append(checks, check{fmt.Sprintf("MSMG2/%d", size), h.msmG2(size)})
+	}
+	checks = append(checks,
+		check{"MSM/ZeroScalars", h.msmZeroScalars},
+		check{"MSM/InfinityPoints", h.msmInfinityPoints},
+		check{"MSMG2/ZeroScalars", h.msmG2ZeroScalars},
+	)
+
+	for logSize := 1; logSize <= config.MaxLogNTT; logSize++ {
+		for _, coset := range []bool{false, true} {
+			checks = append(checks,
+				check{fmt.Sprintf("NTT/%d/coset=%v", logSize, coset), h.ntt(1<<logSize, coset)},
+				check{fmt.Sprintf("INTT/%d/coset=%v", logSize, coset), h.intt(1<<logSize, coset)},
+			)
+		}
+	}
+	checks = append(checks,
+		check{"NTT/Zero", h.nttZero},
+		check{"PolyOps", h.polyOps},
+	)
+
+	for _, c := range checks {
+		t.Run(c.name, c.run)
+	}
+}
+
+type harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac any] struct {
+	curve  iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]
+	ref    Reference[Fr, G1Affine, G1Jac, G2Affine, G2Jac]
+	config Config
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) scalarRoundTrip(t *testing.T) {
+	for _, s := range h.ref.RandomScalars(h.config.Conversions) {
+		assert.Equal(t, s, h.ref.ScalarRoundTrip(s))
+	}
+	var zero Fr
+	assert.Equal(t, zero, h.ref.ScalarRoundTrip(zero))
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) g1RoundTrip(t *testing.T) {
+	for _, p := range h.ref.RandomG1Points(h.config.Conversions) {
+		assert.Equal(t, p, h.ref.G1RoundTrip(p))
+	}
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) g2RoundTrip(t *testing.T) {
+	for _, p := range h.ref.RandomG2Points(h.config.Conversions) {
+		assert.Equal(t, p, h.ref.G2RoundTrip(p))
+	}
+}
+
+// toDevice uploads scalars, which are freed when t ends.
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) toDevice(t *testing.T, scalars []Fr) unsafe.Pointer {
+	scalars_d, err := h.curve.CopyScalarsToDevice(scalars)
+	require.NoError(t, err)
+	t.Cleanup(func() { h.curve.FreeDevicePointer(scalars_d) })
+
+	return scalars_d
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) fromDevice(t *testing.T, scalars_d unsafe.Pointer, size int) []Fr {
+	scalars, err := h.curve.CopyScalarsFromDevice(scalars_d, size)
+	require.NoError(t, err)
+
+	return scalars
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) deviceScalarRoundTrip(t *testing.T) {
+	scalars := h.ref.RandomScalars(h.config.Conversions)
+	scalars_d := h.toDevice(t, scalars)
+
+	assert.Equal(t, scalars, h.fromDevice(t, scalars_d, len(scalars)))
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) checkMSM(t *testing.T, points []G1Affine, scalars []Fr) {
+	scalars_d := h.toDevice(t, scalars)
+	points_d, err := h.curve.CopyG1PointsToDevice(points)
+	require.NoError(t, err)
+	defer h.curve.FreeDevicePointer(points_d)
+
+	res, err := h.curve.MsmOnDevice(scalars_d, points_d, len(scalars))
+	assert.NoError(t, err)
+	assert.True(t, h.ref.EqualG1(h.ref.MultiExpG1(points, scalars), res), "MSM of size %d differs from MultiExp", len(scalars))
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) checkMSMG2(t *testing.T, points []G2Affine, scalars []Fr) {
+	scalars_d := h.toDevice(t, scalars)
+	points_d, err := h.curve.CopyG2PointsToDevice(points)
+	require.NoError(t, err)
+	defer h.curve.FreeDevicePointer(points_d)
+
+	res, err := h.curve.MsmG2OnDevice(scalars_d, points_d, len(scalars))
+	assert.NoError(t, err)
+	assert.True(t, h.ref.EqualG2(h.ref.MultiExpG2(points, scalars), res), "G2 MSM of size %d differs from MultiExp", len(scalars))
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) msm(size int) func(t *testing.T) {
+	return func(t *testing.T) {
+		h.checkMSM(t, h.ref.RandomG1Points(size), h.ref.RandomScalars(size))
+	}
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) msmG2(size int) func(t *testing.T) {
+	return func(t *testing.T) {
+		h.checkMSMG2(t, h.ref.RandomG2Points(size), h.ref.RandomScalars(size))
+	}
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) msmZeroScalars(t *testing.T) {
+	h.checkMSM(t, h.ref.RandomG1Points(16), make([]Fr, 16))
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) msmInfinityPoints(t *testing.T) {
+	// the zero value of a gnark-crypto affine point is the point at infinity
+	points := h.ref.RandomG1Points(16)
+	for i := 0; i < len(points); i += 2 {
+		var infinity G1Affine
+		points[i] = infinity
+	}
+
+	h.checkMSM(t, points, h.ref.RandomScalars(16))
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) msmG2ZeroScalars(t *testing.T) {
+	h.checkMSMG2(t, h.ref.RandomG2Points(16), make([]Fr, 16))
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) runNtt(t *testing.T, values []Fr, coset bool) []Fr {
+	size := len(values)
+	values_d := h.toDevice(t, values)
+	out_d := h.toDevice(t, make([]Fr, size))
+
+	twiddles_d, _ := h.curve.GenerateTwiddleFactors(size, false)
+	defer h.curve.FreeDevicePointer(twiddles_d)
+
+	var cosetPowers_d unsafe.Pointer
+	if coset {
+		cosetPowers_d = h.toDevice(t, h.ref.CosetPowers(size, false))
+	}
+
+	assert.NoError(t, h.curve.NttOnDevice(out_d, values_d, twiddles_d, cosetPowers_d, size, size, coset))
+
+	return h.fromDevice(t, out_d, size)
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) ntt(size int, coset bool) func(t *testing.T) {
+	return func(t *testing.T) {
+		values := h.ref.RandomScalars(size)
+		res := h.runNtt(t, values, coset)
+
+		h.ref.FFT(values, coset)
+		assert.Equal(t, values, res)
+	}
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) intt(size int, coset bool) func(t *testing.T) {
+	return func(t *testing.T) {
+		values := h.ref.RandomScalars(size)
+		values_d := h.toDevice(t, values)
+
+		twiddlesInv_d, _ := h.curve.GenerateTwiddleFactors(size, true)
+		defer h.curve.FreeDevicePointer(twiddlesInv_d)
+
+		var cosetPowersInv_d unsafe.Pointer
+		if coset {
+			cosetPowersInv_d = h.toDevice(t, h.ref.CosetPowers(size, true))
+		}
+
+		out_d, err := h.curve.INttOnDevice(values_d, twiddlesInv_d, cosetPowersInv_d, size, coset)
+		require.NoError(t, err)
+		defer h.curve.FreeDevicePointer(out_d)
+		res := h.fromDevice(t, out_d, size)
+
+		h.ref.FFTInverse(values, coset)
+		assert.Equal(t, values, res)
+	}
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) nttZero(t *testing.T) {
+	zeros := make([]Fr, 1<<4)
+	assert.Equal(t, zeros, h.runNtt(t, make([]Fr, 1<<4), false))
+}
+
+func (h harness[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) polyOps(t *testing.T) {
+	size := 1 << h.config.MaxLogNTT
+	a, b, c, den := h.ref.RandomScalars(size), h.ref.RandomScalars(size), h.ref.RandomScalars(size), h.ref.RandomScalars(size)
+
+	var ptrs []unsafe.Pointer
+	for _, v := range [][]Fr{a, b, c, den} {
+		ptrs = append(ptrs, h.toDevice(t, v))
+	}
+	assert.NoError(t, h.curve.PolyOps(ptrs[0], ptrs[1], ptrs[2], ptrs[3], size))
+
+	h.ref.PolyOps(a, b, c, den)
+	assert.Equal(t, a, h.fromDevice(t, ptrs[0], size))
+}
@@ -0,0 +1,141 @@
+package conformance
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	"github.com/ingonyama-zk/iciclegnark/internal/hostcurve"
+	"github.com/stretchr/testify/assert"
+)
+
+type hostReference struct{}
+
+func (hostReference) RandomScalars(n int) []fr.Element {
+	scalars := make([]fr.Element, n)
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+
+	return scalars
+}
+
+func (r hostReference) RandomG1Points(n int) []bn254.G1Affine {
+	_, _, g1, _ := bn254.Generators()
+	points := make([]bn254.G1Affine, n)
+	for i, s := range r.RandomScalars(n) {
+		points[i].ScalarMultiplication(&g1, s.BigInt(new(big.Int)))
+	}
+
+	return points
+}
+
+func (r hostReference) RandomG2Points(n int) []bn254.G2Affine {
+	_, _, _, g2 := bn254.Generators()
+	points := make([]bn254.G2Affine, n)
+	for i, s := range r.RandomScalars(n) {
+		points[i].ScalarMultiplication(&g2, s.BigInt(new(big.Int)))
+	}
+
+	return points
+}
+
+func (hostReference) MultiExpG1(points []bn254.G1Affine, scalars []fr.Element) bn254.G1Jac {
+	var res bn254.G1Jac
+	res.MultiExp(points, scalars, ecc.MultiExpConfig{})
+
+	return res
+}
+
+func (hostReference) MultiExpG2(points []bn254.G2Affine, scalars []fr.Element) bn254.G2Jac {
+	var res bn254.G2Jac
+	res.MultiExp(points, scalars, ecc.MultiExpConfig{})
+
+	return res
+}
+
+func (hostReference) EqualG1(a, b bn254.G1Jac) bool { return a.Equal(&b) }
+
+func (hostReference) EqualG2(a, b bn254.G2Jac) bool { return a.Equal(&b) }
+
+func (hostReference) FFT(values []fr.Element, coset bool) {
+	domain := fft.NewDomain(uint64(len(values)))
+	if coset {
+		domain.FFT(values, fft.DIF, fft.OnCoset())
+	} else {
+		domain.FFT(values, fft.DIF)
+	}
+	fft.BitReverse(values)
+}
+
+func (hostReference) FFTInverse(values []fr.Element, coset bool) {
+	domain := fft.NewDomain(uint64(len(values)))
+	if coset {
+		domain.FFTInverse(values, fft.DIF, fft.OnCoset())
+	} else {
+		domain.FFTInverse(values, fft.DIF)
+	}
+	fft.BitReverse(values)
+}
+
+func (hostReference) CosetPowers(n int, inverse bool) []fr.Element {
+	domain := fft.NewDomain(uint64(n))
+	if inverse {
+		return domain.CosetTableInv
+	}
+
+	return domain.CosetTable
+}
+
+func (hostReference) PolyOps(a, b, c, den []fr.Element) {
+	for i := range a {
+		a[i].Mul(&a[i], &b[i]).Sub(&a[i], &c[i]).Mul(&a[i], &den[i])
+	}
+}
+
+func (hostReference) ScalarRoundTrip(s fr.Element) fr.Element {
+	var res fr.Element
+	res.SetBytes(s.Marshal())
+
+	return res
+}
+
+func (hostReference) G1RoundTrip(p bn254.G1Affine) bn254.G1Affine {
+	var res bn254.G1Affine
+	res.SetBytes(p.Marshal())
+
+	return res
+}
+
+func (hostReference) G2RoundTrip(p bn254.G2Affine) bn254.G2Affine {
+	var res bn254.G2Affine
+	res.SetBytes(p.Marshal())
+
+	return res
+}
+
+func TestSizes(t *testing.T) {
+	assert.Equal(t, []int{1, 2}, Sizes(1))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 7, 8}, Sizes(3))
+}
+
+func TestRunOnHost(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxLogMSM = 6
+	config.MaxLogG2MSM = 4
+	config.MaxLogNTT = 6
+
+	curve := hostcurve.NewBN254()
+	Run[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac](t, curve, hostReference{}, config)
+
+	assert.Zero(t, curve.Live(), "every device buffer should be freed")
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("ICICLEGNARK_MAX_LOG_MSM", "3")
+	assert.Equal(t, 3, ConfigFromEnv().MaxLogMSM)
+	assert.Equal(t, DefaultConfig().MaxLogNTT, ConfigFromEnv().MaxLogNTT)
+}
@@ -0,0 +1,125 @@
+// Package iciclegnark exposes the per-curve device wrappers under curves/
+// behind a single generic interface, so that provers can be written once and
+// instantiated for any supported curve.
+//
+// Curve packages register themselves when imported:
+//
+//	import _ "github.com/ingonyama-zk/iciclegnark/curves/bn254"
+//
+//	c, err := iciclegnark.Get[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac](ecc.BN254)
+package iciclegnark
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Curve is the device API of one curve expressed with its gnark-crypto types.
+// Device buffers are raw pointers, as in the curve packages.
+//
+// The implementations of the curve packages are safe for concurrent use.
+// Their calls run on the current CUDA device of the calling OS thread; bind
+// them to a device with package devicectx.
+type Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac any] interface {
+	ID() ecc.ID
+
+	// Conversions between gnark-crypto types and the device layout. The
+	// point copies of the curve packages check the points, see
+	// SetPointValidation.
+	CopyScalarsToDevice(scalars []Fr) (unsafe.Pointer, error)
+	CopyG1PointsToDevice(points []G1Affine) (unsafe.Pointer, error)
+	CopyG2PointsToDevice(points []G2Affine) (unsafe.Pointer, error)
+	CopyScalarsFromDevice(scalars_d unsafe.Pointer, size int) ([]Fr, error)
+	FreeDevicePointer(ptr unsafe.Pointer)
+
+	// MSM
+	MsmOnDevice(scalars_d, points_d unsafe.Pointer, count int) (G1Jac, error)
+	MsmG2OnDevice(scalars_d, points_d unsafe.Pointer, count int) (G2Jac, error)
+
+	// NTT
+	GenerateTwiddleFactors(size int, inverse bool) (unsafe.Pointer, error)
+	NttOnDevice(scalars_out, scalars_d, twiddles_d, coset_powers_d unsafe.Pointer, size, twid_size int, isCoset bool) error
+	INttOnDevice(scalars_d, twiddles_d, cosetPowers_d unsafe.Pointer, size int, isCoset bool) (unsafe.Pointer, error)
+	ReverseScalars(ptr unsafe.Pointer, size int) error
+
+	// VecOps
+	PolyOps(a_d, b_d, c_d, den_d unsafe.Pointer, size int) error
+	MontConvOnDevice(scalars_d unsafe.Pointer, size int, is_into bool) error
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     = make(map[ecc.ID]any)
+)
+
+// Register makes a curve implementation available through Get. It is called
+// from the init function of each curve package and panics on duplicates.
+func Register(id ecc.ID, curve any) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if _, ok := registry[id]; ok {
+		panic(fmt.Sprintf("iciclegnark: curve %s registered twice", id))
+	}
+	registry[id] = curve
+}
+
+// Get returns the implementation registered for id. The type arguments must
+// be the gnark-crypto types of that curve.
+func Get[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](id ecc.ID) (Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], error) {
+	registryLock.RLock()
+	c, ok := registry[id]
+	registryLock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("iciclegnark: curve %s is not registered, import its package under curves/", id)
+	}
+
+	curve, ok := c.(Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac])
+	if !ok {
+		return nil, fmt.Errorf("iciclegnark: curve %s does not match the requested types", id)
+	}
+
+	return curve, nil
+}
+
+// Curves lists the registered curves.
+func Curves() []ecc.ID {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	ids := make([]ecc.ID, 0, len(registry))
+	for id := range registry {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+// Commit uploads scalars and points, runs a G1 MSM and frees the device buffers.
+func Commit[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](c Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], scalars []Fr, points []G1Affine) (G1Jac, error) {
+	if len(scalars) != len(points) {
+		var zero G1Jac
+		return zero, fmt.Errorf("iciclegnark: %d scalars for %d points", len(scalars), len(points))
+	}
+
+	var zero G1Jac
+	scalars_d, err := c.CopyScalarsToDevice(scalars)
+	if err != nil {
+		return zero, err
+	}
+	defer c.FreeDevicePointer(scalars_d)
+
+	points_d, err := c.CopyG1PointsToDevice(points)
+	if err != nil {
+		return zero, err
+	}
+	defer c.FreeDevicePointer(points_d)
+
+	return c.MsmOnDevice(scalars_d, points_d, len(scalars))
+}
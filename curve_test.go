@@ -0,0 +1,36 @@
+package iciclegnark
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark/internal/hostcurve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	curve := hostcurve.NewInts(ecc.SECP256K1)
+	Register(ecc.SECP256K1, curve)
+
+	assert.Contains(t, Curves(), ecc.SECP256K1)
+	assert.Panics(t, func() { Register(ecc.SECP256K1, curve) })
+
+	c, err := Get[int, int, int, int, int](ecc.SECP256K1)
+	assert.NoError(t, err)
+	assert.Equal(t, ecc.SECP256K1, c.ID())
+
+	_, err = Get[int, int, int, int, string](ecc.SECP256K1)
+	assert.Error(t, err)
+
+	_, err = Get[int, int, int, int, int](ecc.STARK_CURVE)
+	assert.Error(t, err)
+
+	res, err := Commit(c, []int{1, 2, 3}, []int{4, 5, 6})
+	assert.NoError(t, err)
+	assert.Equal(t, 32, res)
+	assert.Equal(t, 2, curve.Freed())
+	assert.Zero(t, curve.Live())
+
+	_, err = Commit(c, []int{1, 2}, []int{4})
+	assert.Error(t, err)
+}
@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Code generated by Ingonyama DO NOT EDIT
+
 package bls12377
 
 import (
@@ -25,7 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestFieldBN254FromGnark(t *testing.T) {
+func TestFieldBLS12377FromGnark(t *testing.T) {
 	var rand fr.Element
 	rand.SetRandom()
 
@@ -57,7 +59,7 @@ func BenchmarkBatchConvertFromFrGnark(b *testing.B) {
 	})
 }
 
-func TestPointBN254FromGnark(t *testing.T) {
+func TestPointBLS12377FromGnark(t *testing.T) {
 	gnarkP, _ := randG1Jac()
 
 	var f icicle.G1BaseField
@@ -83,7 +85,7 @@ func TestPointBN254FromGnark(t *testing.T) {
 	assert.Equal(t, p.Z, f)
 }
 
-func TestPointAffineNoInfinityBN254ToProjective(t *testing.T) {
+func TestPointAffineNoInfinityBLS12377ToProjective(t *testing.T) {
 	gnarkP, _ := randG1Jac()
 	var f icicle.G1BaseField
 	var p icicle.G1ProjectivePoint
@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Code generated by Ingonyama DO NOT EDIT
+
 package bls12377
 
 import (
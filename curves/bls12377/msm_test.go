@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Code generated by Ingonyama DO NOT EDIT
+
 package bls12377
 
 import (
@@ -82,10 +84,14 @@ func GeneratePoints(count int) ([]icicle.G1PointAffine, []bls12377.G1Affine) {
 	return points[:count], pointsAffine[:count]
 }
 
-func ReadGnarkPointsFromFile(filePath string, size int) (points []icicle.G1PointAffine, gnarkPoints []bls12377.G1Affine) {
+func ReadGnarkPointsFromFile(filePath string, size int) (points []icicle.G1PointAffine, gnarkPoints []bls12377.G1Affine, err error) {
 	points = make([]icicle.G1PointAffine, size)
 	gnarkPoints = make([]bls12377.G1Affine, size)
-	file, _ := os.Open(filePath)
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
 	scanner := bufio.NewScanner(file)
 
 	for i := 0; scanner.Scan(); i++ {
@@ -99,6 +105,9 @@ func ReadGnarkPointsFromFile(filePath string, size int) (points []icicle.G1Point
 		points[i] = *p.StripZ()
 
 	}
+
+	// SRS points read from disk are validated by default
+	err = ValidateG1Points(gnarkPoints, 0)
 	return
 }
 
@@ -222,13 +231,16 @@ func TestCommitMSM(t *testing.T) {
 		scalars, gnarkScalars := GenerateScalars(count, true)
 		fmt.Print("Finished generating scalars\n")
 
-		out_d, _ := goicicle.CudaMalloc(96)
+		var sizeCheckG1Point icicle.G1ProjectivePoint
+		out_d, _ := goicicle.CudaMalloc(int(unsafe.Sizeof(sizeCheckG1Point)))
 
-		pointsBytes := count * 64
+		var sizeCheckG1PointAffine icicle.G1PointAffine
+		pointsBytes := count * int(unsafe.Sizeof(sizeCheckG1PointAffine))
 		points_d, _ := goicicle.CudaMalloc(pointsBytes)
 		goicicle.CudaMemCpyHtoD[icicle.G1PointAffine](points_d, points, pointsBytes)
 
-		scalarBytes := count * 32
+		var sizeCheckScalar icicle.G1ScalarField
+		scalarBytes := count * int(unsafe.Sizeof(sizeCheckScalar))
 		scalars_d, _ := goicicle.CudaMalloc(scalarBytes)
 		goicicle.CudaMemCpyHtoD[icicle.G1ScalarField](scalars_d, scalars, scalarBytes)
 
@@ -237,7 +249,7 @@ func TestCommitMSM(t *testing.T) {
 		fmt.Printf("icicle MSM took: %d ms\n", time.Since(startTime).Milliseconds())
 
 		outHost := make([]icicle.G1ProjectivePoint, 1)
-		goicicle.CudaMemCpyDtoH[icicle.G1ProjectivePoint](outHost, out_d, 96)
+		goicicle.CudaMemCpyDtoH[icicle.G1ProjectivePoint](outHost, out_d, int(unsafe.Sizeof(sizeCheckG1Point)))
 
 		assert.Equal(t, e, 0, "error should be 0")
 		fmt.Print("Finished icicle MSM\n")
@@ -261,13 +273,16 @@ func BenchmarkCommit(b *testing.B) {
 		points, _ := GeneratePoints(msmSize)
 		scalars, _ := GenerateScalars(msmSize, false)
 
-		out_d, _ := goicicle.CudaMalloc(96)
+		var sizeCheckG1Point icicle.G1ProjectivePoint
+		out_d, _ := goicicle.CudaMalloc(int(unsafe.Sizeof(sizeCheckG1Point)))
 
-		pointsBytes := msmSize * 64
+		var sizeCheckG1PointAffine icicle.G1PointAffine
+		pointsBytes := msmSize * int(unsafe.Sizeof(sizeCheckG1PointAffine))
 		points_d, _ := goicicle.CudaMalloc(pointsBytes)
 		goicicle.CudaMemCpyHtoD[icicle.G1PointAffine](points_d, points, pointsBytes)
 
-		scalarBytes := msmSize * 32
+		var sizeCheckScalar icicle.G1ScalarField
+		scalarBytes := msmSize * int(unsafe.Sizeof(sizeCheckScalar))
 		scalars_d, _ := goicicle.CudaMalloc(scalarBytes)
 		goicicle.CudaMemCpyHtoD[icicle.G1ScalarField](scalars_d, scalars, scalarBytes)
 
@@ -296,7 +311,7 @@ func TestBenchMSM(t *testing.T) {
 			a, e := icicle.MsmBatch(&points, &scalars, batchSize, 0)
 
 			if e != nil {
-				t.Errorf("MsmBatchbls12377 returned an error: %v", e)
+				t.Errorf("MsmBatchBLS12377 returned an error: %v", e)
 			}
 
 			if len(a) != batchSize {
@@ -375,10 +390,14 @@ func GenerateG2Points(count int) ([]icicle.G2PointAffine, []bls12377.G2Affine) {
 	return points[:count], pointsAffine[:count]
 }
 
-func ReadGnarkG2PointsFromFile(filePath string, size int) (points []icicle.G2PointAffine, gnarkPoints []bls12377.G2Affine) {
+func ReadGnarkG2PointsFromFile(filePath string, size int) (points []icicle.G2PointAffine, gnarkPoints []bls12377.G2Affine, err error) {
 	points = make([]icicle.G2PointAffine, size)
 	gnarkPoints = make([]bls12377.G2Affine, size)
-	file, _ := os.Open(filePath)
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
 	scanner := bufio.NewScanner(file)
 	for i := 0; scanner.Scan(); i++ {
 		x := scanner.Text()
@@ -398,10 +417,12 @@ func ReadGnarkG2PointsFromFile(filePath string, size int) (points []icicle.G2Poi
 
 		G2AffineFromGnarkAffine(&gnarkPoints[i], &points[i])
 	}
+
+	err = ValidateG2Points(gnarkPoints, 0)
 	return
 }
 
-func TestMsmG2bls12377(t *testing.T) {
+func TestMsmG2BLS12377(t *testing.T) {
 	for _, v := range []int{24} {
 		count := 1 << v
 		points, gnarkPoints := GenerateG2Points(count)
@@ -427,7 +448,7 @@ func TestMsmG2bls12377(t *testing.T) {
 	}
 }
 
-func BenchmarkMsmG2bls12377(b *testing.B) {
+func BenchmarkMsmG2BLS12377(b *testing.B) {
 	LOG_MSM_SIZES := []int{20, 21, 22, 23, 24, 25, 26}
 
 	for _, logMsmSize := range LOG_MSM_SIZES {
@@ -465,7 +486,8 @@ func TestCommitG2MSM(t *testing.T) {
 		points_d, _ := goicicle.CudaMalloc(inputPointsBytes)
 		goicicle.CudaMemCpyHtoD[icicle.G2PointAffine](points_d, points, inputPointsBytes)
 
-		scalarBytes := count * 32
+		var sizeCheckScalar icicle.G1ScalarField
+		scalarBytes := count * int(unsafe.Sizeof(sizeCheckScalar))
 		scalars_d, _ := goicicle.CudaMalloc(scalarBytes)
 		goicicle.CudaMemCpyHtoD[icicle.G1ScalarField](scalars_d, scalars, scalarBytes)
 
@@ -509,7 +531,7 @@ func TestBatchG2MSM(t *testing.T) {
 			a, e := icicle.MsmG2Batch(&points, &scalars, batchSize, 0)
 
 			if e != nil {
-				t.Errorf("MsmBatchbls12377 returned an error: %v", e)
+				t.Errorf("MsmBatchBLS12377 returned an error: %v", e)
 			}
 
 			if len(a) != batchSize {
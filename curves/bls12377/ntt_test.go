@@ -12,6 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Code generated by Ingonyama DO NOT EDIT
+
 package bls12377
 
 import (
@@ -25,7 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestNttBN254BBB(t *testing.T) {
+func TestNttBLS12377BBB(t *testing.T) {
 	count := 1 << 20
 	scalars, frScalars := GenerateScalars(count, false)
 
@@ -50,7 +52,7 @@ func TestNttBN254BBB(t *testing.T) {
 	assert.Equal(t, nttResultTransformedToGnark, frScalars)
 }
 
-func TestNttBN254CompareToGnarkDIF(t *testing.T) {
+func TestNttBLS12377CompareToGnarkDIF(t *testing.T) {
 	count := 1 << 2
 	scalars, frScalars := GenerateScalars(count, false)
 
@@ -75,7 +77,7 @@ func TestNttBN254CompareToGnarkDIF(t *testing.T) {
 	assert.Equal(t, nttResultTransformedToGnark, frScalars)
 }
 
-func TestNttBN254CompareToGnarkDIT(t *testing.T) {
+func TestNttBLS12377CompareToGnarkDIT(t *testing.T) {
 	count := 1 << 2
 	scalars, frScalars := GenerateScalars(count, false)
 
@@ -100,7 +102,7 @@ func TestNttBN254CompareToGnarkDIT(t *testing.T) {
 	assert.Equal(t, nttResultTransformedToGnark, frScalars)
 }
 
-func TestINttBN254CompareToGnarkDIT(t *testing.T) {
+func TestINttBLS12377CompareToGnarkDIT(t *testing.T) {
 	count := 1 << 3
 	scalars, frScalars := GenerateScalars(count, false)
 
@@ -128,7 +130,7 @@ func TestINttBN254CompareToGnarkDIT(t *testing.T) {
 	assert.Equal(t, nttResultTransformedToGnark, frResScalars)
 }
 
-func TestINttBN254CompareToGnarkDIF(t *testing.T) {
+func TestINttBLS12377CompareToGnarkDIF(t *testing.T) {
 	count := 1 << 3
 	scalars, frScalars := GenerateScalars(count, false)
 
@@ -151,7 +153,7 @@ func TestINttBN254CompareToGnarkDIF(t *testing.T) {
 	assert.Equal(t, nttResultTransformedToGnark, frScalars)
 }
 
-func TestNttBN254(t *testing.T) {
+func TestNttBLS12377(t *testing.T) {
 	count := 1 << 3
 
 	scalars, _ := GenerateScalars(count, false)
@@ -171,7 +173,7 @@ func TestNttBN254(t *testing.T) {
 	assert.Equal(t, inttResult, scalars)
 }
 
-func TestNttBatchBN254(t *testing.T) {
+func TestNttBatchBLS12377(t *testing.T) {
 	count := 1 << 5
 	batches := 4
 
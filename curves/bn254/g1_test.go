@@ -11,7 +11,7 @@
 // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 // See the License for the specific language governing permissions and
 // limitations under the License.
-	
+
 // Code generated by Ingonyama DO NOT EDIT
 
 package bn254
@@ -19,11 +19,12 @@ package bn254
 import (
 	"fmt"
 	"testing"
+
 	"github.com/consensys/gnark-crypto/ecc/bn254"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
-	"github.com/stretchr/testify/assert"
 	icicle "github.com/ingonyama-zk/icicle/goicicle/curves/bn254"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestFieldBN254FromGnark(t *testing.T) {
@@ -40,7 +41,7 @@ func BenchmarkBatchConvertFromFrGnarkThreaded(b *testing.B) {
 
 	// for _, routineAmount := range ROUTINES {
 	routineAmount := 7
-	_, scalars_fr := GenerateScalars(1 << 24, false)
+	_, scalars_fr := GenerateScalars(1<<24, false)
 	b.Run(fmt.Sprintf("Convert %d", routineAmount), func(b *testing.B) {
 		for n := 0; n < b.N; n++ {
 			_ = BatchConvertFromFrGnarkThreaded[icicle.G1ScalarField](scalars_fr, routineAmount)
@@ -50,7 +51,7 @@ func BenchmarkBatchConvertFromFrGnarkThreaded(b *testing.B) {
 }
 
 func BenchmarkBatchConvertFromFrGnark(b *testing.B) {
-	_, scalars_fr := GenerateScalars(1 << 24, false)
+	_, scalars_fr := GenerateScalars(1<<24, false)
 	b.Run("BatchConvert 2^24", func(b *testing.B) {
 		for n := 0; n < b.N; n++ {
 			_ = BatchConvertFromFrGnark[icicle.G1ScalarField](scalars_fr)
@@ -64,7 +65,7 @@ func TestPointBN254FromGnark(t *testing.T) {
 	var f icicle.G1BaseField
 	f.SetOne()
 	var p icicle.G1ProjectivePoint
-	G1ProjectivePointFromJacGnark(&p,&gnarkP)
+	G1ProjectivePointFromJacGnark(&p, &gnarkP)
 
 	z_inv := new(fp.Element)
 	z_invsq := new(fp.Element)
@@ -88,9 +89,9 @@ func TestPointAffineNoInfinityBN254ToProjective(t *testing.T) {
 	gnarkP, _ := randG1Jac()
 	var f icicle.G1BaseField
 	var p icicle.G1ProjectivePoint
-	
+
 	f.SetOne()
-	affine := G1ProjectivePointFromJacGnark(&p,&gnarkP).StripZ()
+	affine := G1ProjectivePointFromJacGnark(&p, &gnarkP).StripZ()
 	proj := affine.ToProjective()
 
 	assert.Equal(t, proj.X, affine.X)
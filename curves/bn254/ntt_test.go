@@ -11,7 +11,7 @@
 // WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
 // See the License for the specific language governing permissions and
 // limitations under the License.
-	
+
 // Code generated by Ingonyama DO NOT EDIT
 
 package bn254
@@ -20,10 +20,11 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
-	"github.com/stretchr/testify/assert"
 	icicle "github.com/ingonyama-zk/icicle/goicicle/curves/bn254"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNttBN254BBB(t *testing.T) {
@@ -176,7 +177,7 @@ func TestNttBatchBN254(t *testing.T) {
 	count := 1 << 5
 	batches := 4
 
-	scalars, _ := GenerateScalars(count * batches, false)
+	scalars, _ := GenerateScalars(count*batches, false)
 
 	var scalarVecOfVec [][]icicle.G1ScalarField = make([][]icicle.G1ScalarField, 0)
 
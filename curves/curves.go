@@ -0,0 +1,13 @@
+// Package curves holds one package per supported curve. The curve packages are
+// generated from the templates in internal/generator and must not be edited by hand.
+//
+// BW6-633 and BLS24-315 are open: gnark-crypto supports them but the pinned
+// icicle v0.1.0 has no bindings for them, so they have no package yet.
+//
+// Running the tests of the curve packages without a GPU is open too: their
+// device types come from goicicle, whose cgo bindings link icicle's CUDA
+// libraries, so the packages have no cpu build. The generic code they plug
+// into is tested without a GPU against internal/hostcurve.
+package curves
+
+//go:generate go run ../internal/generator -out .
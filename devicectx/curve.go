@@ -0,0 +1,87 @@
+package devicectx
+
+import (
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark"
+)
+
+// Curve runs every call of the curve it wraps in a DeviceContext.
+type Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac any] struct {
+	ctx   *DeviceContext
+	curve iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]
+}
+
+var _ iciclegnark.Curve[int, int, int, int, int] = (*Curve[int, int, int, int, int])(nil)
+
+// Bind returns c with its calls running in ctx.
+func Bind[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](ctx *DeviceContext, c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac] {
+	return &Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]{ctx: ctx, curve: c}
+}
+
+// Context returns the context the calls run in.
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) Context() *DeviceContext { return c.ctx }
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) ID() ecc.ID { return c.curve.ID() }
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) CopyScalarsToDevice(scalars []Fr) (unsafe.Pointer, error) {
+	return Run(c.ctx, func() (unsafe.Pointer, error) { return c.curve.CopyScalarsToDevice(scalars) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) CopyG1PointsToDevice(points []G1Affine) (unsafe.Pointer, error) {
+	return Run(c.ctx, func() (unsafe.Pointer, error) { return c.curve.CopyG1PointsToDevice(points) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) CopyG2PointsToDevice(points []G2Affine) (unsafe.Pointer, error) {
+	return Run(c.ctx, func() (unsafe.Pointer, error) { return c.curve.CopyG2PointsToDevice(points) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) CopyScalarsFromDevice(scalars_d unsafe.Pointer, size int) ([]Fr, error) {
+	return Run(c.ctx, func() ([]Fr, error) { return c.curve.CopyScalarsFromDevice(scalars_d, size) })
+}
+
+// FreeDevicePointer frees ptr in the context; it does nothing once the
+// context is closed.
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) FreeDevicePointer(ptr unsafe.Pointer) {
+	c.ctx.Do(func() error {
+		c.curve.FreeDevicePointer(ptr)
+		return nil
+	})
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) MsmOnDevice(scalars_d, points_d unsafe.Pointer, count int) (G1Jac, error) {
+	return Run(c.ctx, func() (G1Jac, error) { return c.curve.MsmOnDevice(scalars_d, points_d, count) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) MsmG2OnDevice(scalars_d, points_d unsafe.Pointer, count int) (G2Jac, error) {
+	return Run(c.ctx, func() (G2Jac, error) { return c.curve.MsmG2OnDevice(scalars_d, points_d, count) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) GenerateTwiddleFactors(size int, inverse bool) (unsafe.Pointer, error) {
+	return Run(c.ctx, func() (unsafe.Pointer, error) { return c.curve.GenerateTwiddleFactors(size, inverse) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) NttOnDevice(scalars_out, scalars_d, twiddles_d, coset_powers_d unsafe.Pointer, size, twid_size int, isCoset bool) error {
+	return c.ctx.Do(func() error {
+		return c.curve.NttOnDevice(scalars_out, scalars_d, twiddles_d, coset_powers_d, size, twid_size, isCoset)
+	})
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) INttOnDevice(scalars_d, twiddles_d, cosetPowers_d unsafe.Pointer, size int, isCoset bool) (unsafe.Pointer, error) {
+	return Run(c.ctx, func() (unsafe.Pointer, error) {
+		return c.curve.INttOnDevice(scalars_d, twiddles_d, cosetPowers_d, size, isCoset)
+	})
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) ReverseScalars(ptr unsafe.Pointer, size int) error {
+	return c.ctx.Do(func() error { return c.curve.ReverseScalars(ptr, size) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) PolyOps(a_d, b_d, c_d, den_d unsafe.Pointer, size int) error {
+	return c.ctx.Do(func() error { return c.curve.PolyOps(a_d, b_d, c_d, den_d, size) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) MontConvOnDevice(scalars_d unsafe.Pointer, size int, is_into bool) error {
+	return c.ctx.Do(func() error { return c.curve.MontConvOnDevice(scalars_d, size, is_into) })
+}
@@ -0,0 +1,14 @@
+//go:build cpu
+
+package devicectx
+
+import "fmt"
+
+// setDevice accepts device 0 only: without a GPU there is one host "device".
+func setDevice(device int) error {
+	if device != 0 {
+		return fmt.Errorf("no device %d in the cpu build", device)
+	}
+
+	return nil
+}
@@ -0,0 +1,21 @@
+//go:build !cpu
+
+package devicectx
+
+// #cgo CFLAGS: -I /usr/local/cuda/include
+// #cgo LDFLAGS: -L/usr/local/cuda/lib64 -lcudart
+/*
+#include <cuda_runtime.h>
+*/
+import "C"
+
+import "fmt"
+
+// setDevice makes device current on the calling thread.
+func setDevice(device int) error {
+	if err := C.cudaSetDevice(C.int(device)); err != 0 {
+		return fmt.Errorf("cudaSetDevice failed with code %d", int(err))
+	}
+
+	return nil
+}
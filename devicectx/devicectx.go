@@ -0,0 +1,153 @@
+// Package devicectx runs device calls on one locked OS thread bound to one
+// CUDA device.
+//
+// CUDA keeps the current device per OS thread, and goroutines migrate between
+// threads, so a goroutine that selects a device may run its next call on a
+// thread still bound to another one. A DeviceContext owns a goroutine locked
+// to its thread, selects the device there once, and runs every function it
+// is given on that thread, one at a time:
+//
+//	ctx, err := devicectx.New(1)
+//	defer ctx.Close()
+//	c := devicectx.Bind(ctx, bn254.Curve{})
+//	// every call on c runs on GPU 1, from any goroutine
+//
+// # Concurrency
+//
+// The curve packages keep no state of their own: the logger, observer,
+// verification mode, device hooks and curve registry live in package
+// iciclegnark, whose race tests replace them under concurrent calls. The device calls rely on
+// the CUDA runtime being thread-safe and on icicle running every kernel on
+// the default stream of the current device; that part runs outside Go and is
+// not covered by the race detector. Concurrent calls on one device share its
+// memory without admission control; package scheduler adds that.
+// Calls run on the current device of the calling thread, device 0 unless it
+// was changed, which is what a DeviceContext fixes.
+//
+// Device buffers belong to the device they were allocated on and must only be
+// passed to calls of a context bound to it.
+//
+// Built with the cpu tag there is no GPU and only device 0: the context
+// still pins its calls to one thread, so the same code and tests run under
+// the race detector without CUDA.
+package devicectx
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/ingonyama-zk/iciclegnark"
+)
+
+// ErrClosed is returned for calls on a closed context.
+var ErrClosed = errors.New("devicectx: context closed")
+
+// DeviceContext runs functions on an OS thread bound to one device. It is
+// safe for concurrent use; functions run one at a time, in submission order.
+type DeviceContext struct {
+	device int
+	calls  chan func()
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+}
+
+// New starts the thread of a context bound to device.
+func New(device int) (*DeviceContext, error) {
+	c := &DeviceContext{device: device, calls: make(chan func()), done: make(chan struct{})}
+
+	started := make(chan error)
+	go c.loop(started)
+	if err := <-started; err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *DeviceContext) loop(started chan<- error) {
+	// the thread is never unlocked: it exits with the goroutine, taking its
+	// device binding with it
+	runtime.LockOSThread()
+	defer close(c.done)
+
+	if err := selectDevice(c.device); err != nil {
+		started <- fmt.Errorf("devicectx: selecting device %d: %w", c.device, err)
+		return
+	}
+	close(started)
+
+	for fn := range c.calls {
+		fn()
+	}
+}
+
+// selectDevice runs the SetDevice hook, if any, then makes device current on
+// the calling thread.
+func selectDevice(device int) error {
+	if h := iciclegnark.CurrentDeviceHooks(); h != nil {
+		if err := h.SetDevice(device); err != nil {
+			return err
+		}
+	}
+
+	return setDevice(device)
+}
+
+// Device returns the CUDA id of the device.
+func (c *DeviceContext) Device() int { return c.device }
+
+// Do runs fn on the thread of c and returns its error. A panic in fn is
+// raised again in the caller. fn must not call Do on c.
+func (c *DeviceContext) Do(fn func() error) error {
+	c.mu.RLock()
+	if c.closed {
+		c.mu.RUnlock()
+		return ErrClosed
+	}
+
+	var err error
+	var panicked any
+	done := make(chan struct{})
+	c.calls <- func() {
+		defer close(done)
+		defer func() { panicked = recover() }()
+		err = fn()
+	}
+	c.mu.RUnlock()
+
+	<-done
+	if panicked != nil {
+		panic(panicked)
+	}
+
+	return err
+}
+
+// Run runs fn on the thread of c and returns its results.
+func Run[T any](c *DeviceContext, fn func() (T, error)) (T, error) {
+	var res T
+	err := c.Do(func() error {
+		var err error
+		res, err = fn()
+		return err
+	})
+
+	return res, err
+}
+
+// Close waits for the calls in progress and stops the thread. Later calls
+// return ErrClosed.
+func (c *DeviceContext) Close() {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.calls)
+	}
+	c.mu.Unlock()
+
+	<-c.done
+}
@@ -0,0 +1,103 @@
+package devicectx
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ingonyama-zk/iciclegnark/faults"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDo(t *testing.T) {
+	ctx, err := New(0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, ctx.Device())
+
+	failed := errors.New("failed")
+	assert.NoError(t, ctx.Do(func() error { return nil }))
+	assert.Equal(t, failed, ctx.Do(func() error { return failed }))
+
+	n, err := Run(ctx, func() (int, error) { return 42, nil })
+	assert.NoError(t, err)
+	assert.Equal(t, 42, n)
+
+	// a panic reaches the caller and the context keeps running
+	assert.PanicsWithValue(t, "boom", func() {
+		ctx.Do(func() error { panic("boom") })
+	})
+	assert.NoError(t, ctx.Do(func() error { return nil }))
+
+	ctx.Close()
+	ctx.Close()
+	assert.ErrorIs(t, ctx.Do(func() error { return nil }), ErrClosed)
+}
+
+func TestDoSerializes(t *testing.T) {
+	ctx, err := New(0)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	// unsynchronized state is safe when only touched in the context
+	counter := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ctx.Do(func() error {
+					counter++
+					return nil
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	n, _ := Run(ctx, func() (int, error) { return counter, nil })
+	assert.Equal(t, 1600, n)
+}
+
+func TestCloseWaits(t *testing.T) {
+	ctx, err := New(0)
+	require.NoError(t, err)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- ctx.Do(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	closed := make(chan struct{})
+	go func() {
+		ctx.Close()
+		close(closed)
+	}()
+
+	close(release)
+	assert.NoError(t, <-done)
+	<-closed
+}
+
+func TestNewSelectsDevice(t *testing.T) {
+	d := faults.NewDevice()
+	defer d.Install()()
+
+	ctx, err := New(0)
+	require.NoError(t, err)
+	ctx.Close()
+
+	// the hook sees the device before setDevice, and can fail it
+	d.Inject(faults.FailKernel(faults.CudaSetDevice, 1))
+	_, err = New(3)
+	assert.ErrorIs(t, err, faults.ErrInjected)
+	assert.ErrorContains(t, err, "selecting device 3")
+	assert.Equal(t, []int{0, 3}, d.Selected())
+}
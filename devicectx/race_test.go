@@ -0,0 +1,78 @@
+package devicectx
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	"github.com/ingonyama-zk/iciclegnark/internal/hostcurve"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConcurrentCalls runs MSMs, NTTs and copies from many goroutines on one
+// bound curve; run with -race -tags cpu to check the context serializes them.
+func TestConcurrentCalls(t *testing.T) {
+	ctx, err := New(0)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	host := hostcurve.NewBN254()
+	c := Bind[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac](ctx, host)
+
+	const size = 64
+	points := make([]bn254.G1Affine, size)
+	for i := range points {
+		points[i].ScalarMultiplicationBase(big.NewInt(int64(i + 1)))
+	}
+	points_d, err := c.CopyG1PointsToDevice(points)
+	require.NoError(t, err)
+	twiddles_d, err := c.GenerateTwiddleFactors(size, false)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				scalars := make([]fr.Element, size)
+				for j := range scalars {
+					scalars[j].SetRandom()
+				}
+				scalars_d, err := c.CopyScalarsToDevice(scalars)
+				require.NoError(t, err)
+
+				// MSM with the shared points
+				res, err := c.MsmOnDevice(scalars_d, points_d, size)
+				require.NoError(t, err)
+				var expected bn254.G1Jac
+				expected.MultiExp(points, scalars, ecc.MultiExpConfig{})
+				assert.True(t, expected.Equal(&res))
+
+				// NTT with the shared twiddles
+				out_d, err := c.CopyScalarsToDevice(make([]fr.Element, size))
+				require.NoError(t, err)
+				require.NoError(t, c.NttOnDevice(out_d, scalars_d, twiddles_d, nil, size, size, false))
+				evaluations, err := c.CopyScalarsFromDevice(out_d, size)
+				require.NoError(t, err)
+				fft.NewDomain(size).FFT(scalars, fft.DIF)
+				fft.BitReverse(scalars)
+				assert.Equal(t, scalars, evaluations)
+
+				c.FreeDevicePointer(scalars_d)
+				c.FreeDevicePointer(out_d)
+			}
+		}()
+	}
+	wg.Wait()
+
+	c.FreeDevicePointer(points_d)
+	c.FreeDevicePointer(twiddles_d)
+	n, _ := Run(ctx, func() (int, error) { return host.Live(), nil })
+	assert.Zero(t, n)
+}
@@ -0,0 +1,32 @@
+package devicectx
+
+import (
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPinnedThread(t *testing.T) {
+	ctx, err := New(0)
+	require.NoError(t, err)
+	defer ctx.Close()
+
+	thread, err := Run(ctx, func() (int, error) { return syscall.Gettid(), nil })
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				tid, _ := Run(ctx, func() (int, error) { return syscall.Gettid(), nil })
+				assert.Equal(t, thread, tid)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,243 @@
+// Package deviceset shards MSMs and batches of NTTs across several devices.
+//
+// An MSM is split into contiguous ranges, one per device and proportional to
+// the device memory budgets; a range larger than its device budget runs as
+// several sequential chunks. The partial Jacobian results are summed on the
+// host in device order, so the result does not depend on scheduling.
+//
+// Each NTT of a batch runs whole on one device, the least loaded one whose
+// budget fits it.
+//
+// Curve packages provide GPU devices through NewDeviceSet; any implementation
+// of Device, such as a host simulation, can be used in their place.
+package deviceset
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Device runs one shard of work.
+type Device[Fr, G1Affine, G1Jac, G2Affine, G2Jac any] interface {
+	// ID is the CUDA device id.
+	ID() int
+	// MemoryBudget is the number of bytes one shard may use on the device.
+	MemoryBudget() int
+
+	Msm(scalars []Fr, points []G1Affine) (G1Jac, error)
+	MsmG2(scalars []Fr, points []G2Affine) (G2Jac, error)
+	// Ntt transforms values in place, natural order in and out.
+	Ntt(values []Fr, inverse bool) error
+}
+
+// Ops describes the curve types to a Set: their sizes on device, counted
+// against memory budgets, and how to add partial MSM results.
+type Ops[G1Jac, G2Jac any] struct {
+	ScalarBytes   int
+	G1AffineBytes int
+	G2AffineBytes int
+
+	// AddG1 and AddG2 set acc to acc + p.
+	AddG1 func(acc, p *G1Jac)
+	AddG2 func(acc, p *G2Jac)
+}
+
+// Set shards work across a fixed list of devices.
+type Set[Fr, G1Affine, G1Jac, G2Affine, G2Jac any] struct {
+	devices []Device[Fr, G1Affine, G1Jac, G2Affine, G2Jac]
+	ops     Ops[G1Jac, G2Jac]
+}
+
+// New returns a Set over devices, which must have positive memory budgets.
+func New[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](devices []Device[Fr, G1Affine, G1Jac, G2Affine, G2Jac], ops Ops[G1Jac, G2Jac]) (*Set[Fr, G1Affine, G1Jac, G2Affine, G2Jac], error) {
+	if len(devices) == 0 {
+		return nil, errors.New("deviceset: no devices")
+	}
+	for _, d := range devices {
+		if d.MemoryBudget() <= 0 {
+			return nil, fmt.Errorf("deviceset: device %d has memory budget %d", d.ID(), d.MemoryBudget())
+		}
+	}
+
+	return &Set[Fr, G1Affine, G1Jac, G2Affine, G2Jac]{devices: devices, ops: ops}, nil
+}
+
+// Devices returns the devices of the set.
+func (s *Set[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) Devices() []Device[Fr, G1Affine, G1Jac, G2Affine, G2Jac] {
+	return s.devices
+}
+
+// Close closes the devices that have a Close method, such as the GPU devices
+// of the curve packages.
+func (s *Set[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) Close() {
+	for _, d := range s.devices {
+		if c, ok := d.(interface{ Close() }); ok {
+			c.Close()
+		}
+	}
+}
+
+// Msm computes the G1 MSM of scalars and points across the devices.
+func (s *Set[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) Msm(scalars []Fr, points []G1Affine) (G1Jac, error) {
+	return msm(s, scalars, points, s.ops.G1AffineBytes, s.ops.AddG1,
+		func(d Device[Fr, G1Affine, G1Jac, G2Affine, G2Jac], scalars []Fr, points []G1Affine) (G1Jac, error) {
+			return d.Msm(scalars, points)
+		})
+}
+
+// MsmG2 is the G2 counterpart of Msm.
+func (s *Set[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) MsmG2(scalars []Fr, points []G2Affine) (G2Jac, error) {
+	return msm(s, scalars, points, s.ops.G2AffineBytes, s.ops.AddG2,
+		func(d Device[Fr, G1Affine, G1Jac, G2Affine, G2Jac], scalars []Fr, points []G2Affine) (G2Jac, error) {
+			return d.MsmG2(scalars, points)
+		})
+}
+
+func msm[Fr, G1Affine, G1Jac, G2Affine, G2Jac, P, J any](
+	s *Set[Fr, G1Affine, G1Jac, G2Affine, G2Jac],
+	scalars []Fr, points []P, pointBytes int,
+	add func(acc, p *J),
+	run func(d Device[Fr, G1Affine, G1Jac, G2Affine, G2Jac], scalars []Fr, points []P) (J, error),
+) (J, error) {
+	var res J
+	if len(scalars) != len(points) {
+		return res, fmt.Errorf("deviceset: %d scalars for %d points", len(scalars), len(points))
+	}
+
+	shards, err := s.plan(len(scalars), s.ops.ScalarBytes+pointBytes)
+	if err != nil {
+		return res, err
+	}
+
+	// shards of one device run in order on that device, devices run concurrently
+	partials := make([]J, len(shards))
+	errs := make([]error, len(shards))
+	var wg sync.WaitGroup
+	for device := range s.devices {
+		wg.Add(1)
+		go func(device int) {
+			defer wg.Done()
+			for i, shard := range shards {
+				if shard.Device != device {
+					continue
+				}
+				partials[i], errs[i] = run(s.devices[device], scalars[shard.Start:shard.End], points[shard.Start:shard.End])
+				if errs[i] != nil {
+					errs[i] = fmt.Errorf("deviceset: device %d, elements [%d, %d): %w", s.devices[device].ID(), shard.Start, shard.End, errs[i])
+					return
+				}
+			}
+		}(device)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return res, err
+	}
+
+	res = partials[0]
+	for i := 1; i < len(partials); i++ {
+		add(&res, &partials[i])
+	}
+
+	return res, nil
+}
+
+// Shard is a range of elements assigned to the device at index Device.
+type Shard struct {
+	Device     int
+	Start, End int
+}
+
+// plan splits count elements of elementBytes each into shards, giving every
+// device a share proportional to its budget and cutting each share into
+// chunks that fit the budget.
+func (s *Set[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) plan(count, elementBytes int) ([]Shard, error) {
+	if count == 0 {
+		return nil, errors.New("deviceset: empty MSM")
+	}
+
+	total := 0
+	for _, d := range s.devices {
+		total += d.MemoryBudget()
+	}
+
+	var shards []Shard
+	start := 0
+	for i, d := range s.devices {
+		end := start + int(int64(count)*int64(d.MemoryBudget())/int64(total))
+		if i == len(s.devices)-1 {
+			end = count
+		}
+		if start == end {
+			continue
+		}
+
+		chunk := d.MemoryBudget() / elementBytes
+		if chunk == 0 {
+			return nil, fmt.Errorf("deviceset: device %d budget of %d bytes is below one element of %d bytes", d.ID(), d.MemoryBudget(), elementBytes)
+		}
+
+		for ; start < end; start += chunk {
+			shards = append(shards, Shard{Device: i, Start: start, End: min(start+chunk, end)})
+		}
+		start = end
+	}
+
+	return shards, nil
+}
+
+// NttBatch transforms every vector of batch in place, natural order in and
+// out, running independent NTTs on different devices.
+func (s *Set[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) NttBatch(batch [][]Fr, inverse bool) error {
+	assignment, err := s.assign(batch)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, len(batch))
+	var wg sync.WaitGroup
+	for device := range s.devices {
+		wg.Add(1)
+		go func(device int) {
+			defer wg.Done()
+			for i, values := range batch {
+				if assignment[i] != device {
+					continue
+				}
+				if err := s.devices[device].Ntt(values, inverse); err != nil {
+					errs[i] = fmt.Errorf("deviceset: device %d, ntt %d: %w", s.devices[device].ID(), i, err)
+				}
+			}
+		}(device)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// assign picks, for every NTT of batch in order, the device with the fewest
+// bytes assigned so far among those whose budget fits it.
+func (s *Set[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) assign(batch [][]Fr) ([]int, error) {
+	load := make([]int, len(s.devices))
+	assignment := make([]int, len(batch))
+	for i, values := range batch {
+		bytes := len(values) * s.ops.ScalarBytes
+
+		best := -1
+		for j, d := range s.devices {
+			if bytes <= d.MemoryBudget() && (best == -1 || load[j] < load[best]) {
+				best = j
+			}
+		}
+		if best == -1 {
+			return nil, fmt.Errorf("deviceset: ntt %d of %d bytes fits no device budget", i, bytes)
+		}
+
+		assignment[i] = best
+		load[best] += bytes
+	}
+
+	return assignment, nil
+}
@@ -0,0 +1,257 @@
+package deviceset
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	"github.com/stretchr/testify/assert"
+)
+
+type bn254Device = Device[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac]
+
+var bn254Ops = Ops[bn254.G1Jac, bn254.G2Jac]{
+	ScalarBytes:   fr.Bytes,
+	G1AffineBytes: 64,
+	G2AffineBytes: 128,
+	AddG1:         func(acc, p *bn254.G1Jac) { acc.AddAssign(p) },
+	AddG2:         func(acc, p *bn254.G2Jac) { acc.AddAssign(p) },
+}
+
+// simulatedDevice computes on the host and records the work it was given.
+type simulatedDevice struct {
+	id     int
+	budget int
+	fail   bool
+
+	mu       sync.Mutex
+	msmSizes []int
+	ntts     int
+}
+
+// closingDevice records its Close.
+type closingDevice struct {
+	*simulatedDevice
+	closed bool
+}
+
+func (d *closingDevice) Close() { d.closed = true }
+
+func (d *simulatedDevice) ID() int           { return d.id }
+func (d *simulatedDevice) MemoryBudget() int { return d.budget }
+
+func (d *simulatedDevice) record(msmSize int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if msmSize > 0 {
+		d.msmSizes = append(d.msmSizes, msmSize)
+	} else {
+		d.ntts++
+	}
+	if d.fail {
+		return errors.New("simulated failure")
+	}
+
+	return nil
+}
+
+func (d *simulatedDevice) Msm(scalars []fr.Element, points []bn254.G1Affine) (bn254.G1Jac, error) {
+	var res bn254.G1Jac
+	if err := d.record(len(scalars)); err != nil {
+		return res, err
+	}
+	_, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{})
+
+	return res, err
+}
+
+func (d *simulatedDevice) MsmG2(scalars []fr.Element, points []bn254.G2Affine) (bn254.G2Jac, error) {
+	var res bn254.G2Jac
+	if err := d.record(len(scalars)); err != nil {
+		return res, err
+	}
+	_, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{})
+
+	return res, err
+}
+
+func (d *simulatedDevice) Ntt(values []fr.Element, inverse bool) error {
+	if err := d.record(0); err != nil {
+		return err
+	}
+
+	domain := fft.NewDomain(uint64(len(values)))
+	if inverse {
+		domain.FFTInverse(values, fft.DIF)
+	} else {
+		domain.FFT(values, fft.DIF)
+	}
+	fft.BitReverse(values)
+
+	return nil
+}
+
+func newSimulatedSet(t *testing.T, budgets ...int) (*Set[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac], []*simulatedDevice) {
+	simulated := make([]*simulatedDevice, len(budgets))
+	devices := make([]bn254Device, len(budgets))
+	for i, budget := range budgets {
+		simulated[i] = &simulatedDevice{id: i, budget: budget}
+		devices[i] = simulated[i]
+	}
+
+	set, err := New(devices, bn254Ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return set, simulated
+}
+
+func randomInputs(n int) ([]fr.Element, []bn254.G1Affine, []bn254.G2Affine) {
+	scalars := make([]fr.Element, n)
+	g1 := make([]bn254.G1Affine, n)
+	g2 := make([]bn254.G2Affine, n)
+	for i := range scalars {
+		scalars[i].SetRandom()
+
+		var s fr.Element
+		s.SetRandom()
+		g1[i].ScalarMultiplicationBase(s.BigInt(new(big.Int)))
+		g2[i].ScalarMultiplicationBase(s.BigInt(new(big.Int)))
+	}
+
+	return scalars, g1, g2
+}
+
+func TestMsmMatchesHost(t *testing.T) {
+	scalars, g1, g2 := randomInputs(1000)
+
+	var expected bn254.G1Jac
+	expected.MultiExp(g1, scalars, ecc.MultiExpConfig{})
+	var expectedG2 bn254.G2Jac
+	expectedG2.MultiExp(g2, scalars, ecc.MultiExpConfig{})
+
+	for _, budgets := range [][]int{
+		{1 << 20},
+		{1 << 20, 1 << 20, 1 << 20},
+		{160 * 100, 160 * 7, 1 << 20, 160},
+	} {
+		set, _ := newSimulatedSet(t, budgets...)
+
+		res, err := set.Msm(scalars, g1)
+		assert.NoError(t, err)
+		assert.True(t, res.Equal(&expected), "budgets %v", budgets)
+
+		resG2, err := set.MsmG2(scalars, g2)
+		assert.NoError(t, err)
+		assert.True(t, resG2.Equal(&expectedG2), "budgets %v", budgets)
+	}
+}
+
+func TestMsmRespectsBudgets(t *testing.T) {
+	scalars, g1, _ := randomInputs(300)
+	elementBytes := bn254Ops.ScalarBytes + bn254Ops.G1AffineBytes
+
+	set, devices := newSimulatedSet(t, 10*elementBytes, 30*elementBytes)
+	_, err := set.Msm(scalars, g1)
+	assert.NoError(t, err)
+
+	// shares are proportional to budgets: 75 and 225 elements
+	assert.Equal(t, []int{10, 10, 10, 10, 10, 10, 10, 5}, devices[0].msmSizes)
+	assert.Equal(t, []int{30, 30, 30, 30, 30, 30, 30, 15}, devices[1].msmSizes)
+}
+
+func TestPlan(t *testing.T) {
+	set, _ := newSimulatedSet(t, 100, 100, 200)
+
+	shards, err := set.plan(10, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []Shard{{0, 0, 2}, {1, 2, 4}, {2, 4, 10}}, shards)
+
+	shards, err = set.plan(1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []Shard{{2, 0, 1}}, shards)
+
+	_, err = set.plan(0, 10)
+	assert.Error(t, err)
+
+	_, err = set.plan(10, 101)
+	assert.Error(t, err)
+}
+
+func TestMsmErrors(t *testing.T) {
+	scalars, g1, _ := randomInputs(8)
+	set, devices := newSimulatedSet(t, 1<<20, 1<<20)
+
+	_, err := set.Msm(scalars[:7], g1)
+	assert.Error(t, err)
+
+	devices[1].fail = true
+	_, err = set.Msm(scalars, g1)
+	assert.ErrorContains(t, err, "device 1")
+}
+
+func TestNttBatch(t *testing.T) {
+	set, devices := newSimulatedSet(t, 1<<10, 1<<16)
+
+	batch := make([][]fr.Element, 6)
+	expected := make([][]fr.Element, len(batch))
+	for i := range batch {
+		size := 1 << (i + 2)
+		batch[i] = make([]fr.Element, size)
+		for j := range batch[i] {
+			batch[i][j].SetRandom()
+		}
+
+		expected[i] = append([]fr.Element{}, batch[i]...)
+		fft.NewDomain(uint64(size)).FFT(expected[i], fft.DIF)
+		fft.BitReverse(expected[i])
+	}
+
+	assert.NoError(t, set.NttBatch(batch, false))
+	assert.Equal(t, expected, batch)
+	assert.Equal(t, 6, devices[0].ntts+devices[1].ntts)
+	assert.NotZero(t, devices[0].ntts)
+	assert.NotZero(t, devices[1].ntts)
+
+	assert.NoError(t, set.NttBatch(batch, true))
+	for i := range batch {
+		fft.NewDomain(uint64(len(expected[i]))).FFTInverse(expected[i], fft.DIF)
+		fft.BitReverse(expected[i])
+	}
+	assert.Equal(t, expected, batch)
+}
+
+func TestAssign(t *testing.T) {
+	set, _ := newSimulatedSet(t, 4*fr.Bytes, 16*fr.Bytes)
+
+	assignment, err := set.assign([][]fr.Element{make([]fr.Element, 4), make([]fr.Element, 8), make([]fr.Element, 2), make([]fr.Element, 2)})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 0, 0}, assignment)
+
+	_, err = set.assign([][]fr.Element{make([]fr.Element, 32)})
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac](nil, bn254Ops)
+	assert.Error(t, err)
+
+	_, err = New([]bn254Device{&simulatedDevice{budget: 0}}, bn254Ops)
+	assert.Error(t, err)
+}
+
+func TestClose(t *testing.T) {
+	closing := &closingDevice{simulatedDevice: &simulatedDevice{budget: 1}}
+	set, err := New([]bn254Device{&simulatedDevice{budget: 1}, closing}, bn254Ops)
+	assert.NoError(t, err)
+
+	set.Close()
+	assert.True(t, closing.closed)
+}
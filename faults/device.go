@@ -0,0 +1,113 @@
+package faults
+
+import (
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark"
+)
+
+// Device calls faults apply to, named after the goicicle and icicle functions
+// the curve packages run. ReverseScalars is also one of them.
+const (
+	CudaMalloc       = "CudaMalloc"
+	CudaMemCpyHtoD   = "CudaMemCpyHtoD"
+	GenerateTwiddles = "GenerateTwiddles"
+	Interpolate      = "Interpolate"
+	Evaluate         = "Evaluate"
+	Commit           = "Commit"
+	CommitG2         = "CommitG2"
+	ToMontgomery     = "ToMontgomery"
+	FromMontgomery   = "FromMontgomery"
+	VecScalarAdd     = "VecScalarAdd"
+	VecScalarSub     = "VecScalarSub"
+	VecScalarMulMod  = "VecScalarMulMod"
+	CudaSetDevice    = "CudaSetDevice"
+)
+
+// Device injects faults into the device calls of the curve packages, below
+// their error handling, and tracks every device buffer they allocate and
+// free. Install it with iciclegnark.SetDeviceHooks, or Install.
+//
+// A Kernel fault returns its Code as the status of the kernel, which must not
+// be zero; on CudaSetDevice it fails the selection with a StatusError. A
+// Corruption fault zeroes one element of the output of the kernel on the
+// device.
+type Device struct {
+	injector
+
+	selected []int // guarded by mu
+}
+
+var _ iciclegnark.DeviceHooks = (*Device)(nil)
+
+// NewDevice returns hooks without faults.
+func NewDevice() *Device {
+	return &Device{injector: newInjector()}
+}
+
+// Install installs d for all curves and returns a function restoring the
+// previous hooks.
+func (d *Device) Install() (restore func()) {
+	previous := iciclegnark.CurrentDeviceHooks()
+	iciclegnark.SetDeviceHooks(d)
+
+	return func() { iciclegnark.SetDeviceHooks(previous) }
+}
+
+func (d *Device) Allocate(_ ecc.ID, op string, _ int) error {
+	d.delay(op)
+
+	return d.allocation(op)
+}
+
+func (d *Device) Allocated(_ ecc.ID, op string, ptr unsafe.Pointer) {
+	d.track(op, ptr)
+}
+
+func (d *Device) Free(_ ecc.ID, ptr unsafe.Pointer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.untrackLocked(ptr)
+}
+
+func (d *Device) Kernel(_ ecc.ID, op string) int {
+	d.delay(op)
+	if f, ok := d.fire(Kernel, op); ok {
+		return f.Code
+	}
+
+	return 0
+}
+
+func (d *Device) SetDevice(device int) error {
+	d.delay(CudaSetDevice)
+
+	d.mu.Lock()
+	d.selected = append(d.selected, device)
+	d.mu.Unlock()
+
+	if f, ok := d.fire(Kernel, CudaSetDevice); ok {
+		return &StatusError{Op: CudaSetDevice, Code: f.Code}
+	}
+
+	return nil
+}
+
+// Selected returns the devices threads selected under the hooks, failed
+// selections included, in order.
+func (d *Device) Selected() []int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return append([]int{}, d.selected...)
+}
+
+func (d *Device) Corrupt(_ ecc.ID, op string) (int, bool) {
+	if f, ok := d.fire(Corruption, op); ok {
+		return f.Index, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,83 @@
+package faults
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceAllocations(t *testing.T) {
+	d := NewDevice()
+	d.Inject(FailAllocation(2))
+	buffers := make([]byte, 2)
+
+	assert.NoError(t, d.Allocate(ecc.BN254, CudaMalloc, 32))
+	d.Allocated(ecc.BN254, CudaMalloc, unsafe.Pointer(&buffers[0]))
+
+	err := d.Allocate(ecc.BN254, Interpolate, 32)
+	assert.True(t, errors.Is(err, ErrInjected))
+	var allocation *AllocationError
+	assert.True(t, errors.As(err, &allocation))
+	assert.Equal(t, Interpolate, allocation.Op)
+	assert.Equal(t, 2, allocation.N)
+
+	assert.NoError(t, d.Allocate(ecc.BN254, GenerateTwiddles, 32))
+	d.Allocated(ecc.BN254, GenerateTwiddles, unsafe.Pointer(&buffers[1]))
+	assert.Equal(t, 2, d.Live())
+
+	d.Free(ecc.BN254, unsafe.Pointer(&buffers[0]))
+	d.Free(ecc.BN254, unsafe.Pointer(&buffers[1]))
+	d.Free(ecc.BN254, unsafe.Pointer(&buffers[1]))
+	d.Free(ecc.BN254, nil)
+	assert.Zero(t, d.Live())
+	assert.Equal(t, 1, d.BadFrees())
+}
+
+func TestDeviceKernels(t *testing.T) {
+	d := NewDevice()
+	d.Inject(FailKernel(Commit, 3), Corrupt(Evaluate, 5), DelayCopy(CudaMemCpyHtoD, 20*time.Millisecond))
+
+	assert.Equal(t, 3, d.Kernel(ecc.BN254, Commit))
+	assert.Zero(t, d.Kernel(ecc.BN254, CommitG2))
+
+	index, ok := d.Corrupt(ecc.BN254, Evaluate)
+	assert.True(t, ok)
+	assert.Equal(t, 5, index)
+	_, ok = d.Corrupt(ecc.BN254, Interpolate)
+	assert.False(t, ok)
+
+	start := time.Now()
+	assert.Zero(t, d.Kernel(ecc.BN254, CudaMemCpyHtoD))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	d.Reset()
+	assert.Zero(t, d.Kernel(ecc.BN254, Commit))
+}
+
+func TestDeviceInstall(t *testing.T) {
+	outer, inner := NewDevice(), NewDevice()
+
+	restoreOuter := outer.Install()
+	restoreInner := inner.Install()
+	assert.Same(t, inner, iciclegnark.CurrentDeviceHooks())
+	restoreInner()
+	assert.Same(t, outer, iciclegnark.CurrentDeviceHooks())
+	restoreOuter()
+	assert.Nil(t, iciclegnark.CurrentDeviceHooks())
+}
+
+func TestDeviceSetDevice(t *testing.T) {
+	d := NewDevice()
+	assert.NoError(t, d.SetDevice(1))
+
+	d.Inject(FailKernel(CudaSetDevice, 2))
+	err := d.SetDevice(3)
+	assert.True(t, errors.Is(err, ErrInjected))
+	assert.EqualError(t, err, "faults: CudaSetDevice returned status 2")
+	assert.Equal(t, []int{1, 3}, d.Selected())
+}
@@ -0,0 +1,459 @@
+// Package faults injects device failures into the curves: a failed
+// allocation, a kernel returning a non-zero status, a corrupted output element
+// or a slow copy. It also tracks the buffers allocated under the faults, so
+// that tests can check error paths free what they allocated.
+//
+// Wrap injects them at the boundary of an iciclegnark.Curve, without a GPU:
+//
+//	curve, _ := iciclegnark.Get[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac](ecc.BN254)
+//	c := faults.Wrap(curve)
+//	c.Inject(faults.FailAllocation(2))
+//	_, err := iciclegnark.Commit(c, scalars, points)
+//	// errors.Is(err, faults.ErrInjected) and c.Live() == 0
+//
+// Faults are then matched against the name of the Curve method called, see
+// the constants below.
+//
+// Device injects them below the curve packages, into the goicicle and icicle
+// calls those run, so that the cleanup and verification of the packages
+// themselves are exercised:
+//
+//	d := faults.NewDevice()
+//	defer d.Install()()
+//	d.Inject(faults.FailKernel(faults.Commit, 3))
+//	_, err := bn254.Curve{}.MsmOnDevice(scalars_d, points_d, count)
+//	// err != nil and d.Live() == 0
+//
+// Faults are then matched against the name of the device call, see the
+// constants in device.go.
+package faults
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark"
+)
+
+// Curve methods faults apply to.
+const (
+	CopyScalarsToDevice    = "CopyScalarsToDevice"
+	CopyG1PointsToDevice   = "CopyG1PointsToDevice"
+	CopyG2PointsToDevice   = "CopyG2PointsToDevice"
+	CopyScalarsFromDevice  = "CopyScalarsFromDevice"
+	MsmOnDevice            = "MsmOnDevice"
+	MsmG2OnDevice          = "MsmG2OnDevice"
+	GenerateTwiddleFactors = "GenerateTwiddleFactors"
+	NttOnDevice            = "NttOnDevice"
+	INttOnDevice           = "INttOnDevice"
+	ReverseScalars         = "ReverseScalars"
+	PolyOps                = "PolyOps"
+	MontConvOnDevice       = "MontConvOnDevice"
+)
+
+// ErrInjected is matched by every error returned for an injected fault.
+var ErrInjected = errors.New("faults: injected failure")
+
+// AllocationError is returned by a call whose allocation was failed.
+type AllocationError struct {
+	Op string
+	// N counts the allocations under the faults, this one included.
+	N int
+}
+
+func (e *AllocationError) Error() string {
+	return fmt.Sprintf("faults: allocation %d (%s) failed", e.N, e.Op)
+}
+
+func (e *AllocationError) Is(target error) bool { return target == ErrInjected }
+
+// StatusError is returned by a call of Wrap whose kernel was failed, and by
+// a device selection failed under Device.
+type StatusError struct {
+	Op   string
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("faults: %s returned status %d", e.Op, e.Code)
+}
+
+func (e *StatusError) Is(target error) bool { return target == ErrInjected }
+
+// Kind is what a Fault does.
+type Kind int
+
+const (
+	// Allocation fails an allocating call: with Wrap the copies to the
+	// device, GenerateTwiddleFactors and INttOnDevice, with Device
+	// CudaMalloc, GenerateTwiddles and Interpolate.
+	Allocation Kind = iota
+	// Kernel fails a call without running it: with Wrap it returns a
+	// StatusError, with Device the kernel returns Code.
+	Kernel
+	// Corruption replaces one element of the output of a call with the zero
+	// value of its type. With Wrap a corrupted device buffer reads back
+	// corrupted with CopyScalarsFromDevice until it is freed and an MSM
+	// result is returned corrupted; with Device the element is zeroed on the
+	// device.
+	Corruption
+	// Delay sleeps before a call.
+	Delay
+)
+
+// Fault is one programmed failure.
+type Fault struct {
+	Kind Kind
+	// Op is the method the fault applies to, empty for every method.
+	Op string
+	// Call is the matching call the fault fires on, counting from 1. Zero
+	// fires on every matching call.
+	Call int
+
+	Code     int           // status of a Kernel fault
+	Index    int           // element changed by a Corruption fault
+	Duration time.Duration // sleep of a Delay fault
+}
+
+// FailAllocation fails the nth allocation under the faults.
+func FailAllocation(n int) Fault {
+	return Fault{Kind: Allocation, Call: n}
+}
+
+// FailKernel makes every call of op fail with code.
+func FailKernel(op string, code int) Fault {
+	return Fault{Kind: Kernel, Op: op, Code: code}
+}
+
+// Corrupt corrupts element index of the output of every call of op.
+func Corrupt(op string, index int) Fault {
+	return Fault{Kind: Corruption, Op: op, Index: index}
+}
+
+// DelayCopy delays every call of op, usually one of the copies, by d.
+func DelayCopy(op string, d time.Duration) Fault {
+	return Fault{Kind: Delay, Op: op, Duration: d}
+}
+
+type armed struct {
+	Fault
+	calls int
+}
+
+// injector holds the programmed faults and the buffers allocated under them,
+// shared by Curve and Device.
+type injector struct {
+	mu          sync.Mutex
+	faults      []*armed
+	allocations int
+	live        map[unsafe.Pointer]string
+	badFrees    int
+}
+
+func newInjector() injector {
+	return injector{live: make(map[unsafe.Pointer]string)}
+}
+
+// Inject adds faults. Calls are counted from the time a fault is injected.
+func (in *injector) Inject(faults ...Fault) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	for _, f := range faults {
+		in.faults = append(in.faults, &armed{Fault: f})
+	}
+}
+
+// Reset removes every fault. Buffers stay tracked.
+func (in *injector) Reset() {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	in.faults = nil
+}
+
+// Live returns the number of buffers allocated and not freed.
+func (in *injector) Live() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return len(in.live)
+}
+
+// BadFrees returns the number of frees of buffers not allocated under the
+// faults or already freed.
+func (in *injector) BadFrees() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return in.badFrees
+}
+
+// fire counts a call of op against the faults of kind and returns the first
+// one firing.
+func (in *injector) fire(kind Kind, op string) (Fault, bool) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return in.fireLocked(kind, op)
+}
+
+func (in *injector) fireLocked(kind Kind, op string) (Fault, bool) {
+	var fired *armed
+	for _, f := range in.faults {
+		if f.Kind != kind || (f.Op != "" && f.Op != op) {
+			continue
+		}
+		f.calls++
+		if fired == nil && (f.Call == 0 || f.Call == f.calls) {
+			fired = f
+		}
+	}
+	if fired == nil {
+		return Fault{}, false
+	}
+
+	return fired.Fault, true
+}
+
+// delay runs the Delay faults of a call of op.
+func (in *injector) delay(op string) {
+	if f, ok := in.fire(Delay, op); ok {
+		time.Sleep(f.Duration)
+	}
+}
+
+// allocation counts an allocation of op and fails it if an Allocation fault
+// fires.
+func (in *injector) allocation(op string) error {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	in.allocations++
+	if _, failed := in.fireLocked(Allocation, op); failed {
+		return &AllocationError{Op: op, N: in.allocations}
+	}
+
+	return nil
+}
+
+// track records ptr, allocated by op, as live.
+func (in *injector) track(op string, ptr unsafe.Pointer) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	in.live[ptr] = op
+}
+
+// untrackLocked records the free of ptr and reports whether it was live.
+func (in *injector) untrackLocked(ptr unsafe.Pointer) bool {
+	if _, ok := in.live[ptr]; ok {
+		delete(in.live, ptr)
+		return true
+	}
+	if ptr != nil {
+		in.badFrees++
+	}
+
+	return false
+}
+
+// Curve is an iciclegnark.Curve running on another one, with faults.
+type Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac any] struct {
+	injector
+	inner iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]
+
+	corrupted map[unsafe.Pointer]int // guarded by mu
+}
+
+var _ iciclegnark.Curve[int, int, int, int, int] = (*Curve[int, int, int, int, int])(nil)
+
+// Wrap returns c without faults.
+func Wrap[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac] {
+	return &Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]{
+		injector:  newInjector(),
+		inner:     c,
+		corrupted: make(map[unsafe.Pointer]int),
+	}
+}
+
+// before runs the Delay and Kernel faults of a call of op.
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) before(op string) error {
+	c.delay(op)
+	if f, ok := c.fire(Kernel, op); ok {
+		return &StatusError{Op: op, Code: f.Code}
+	}
+
+	return nil
+}
+
+// allocate runs fn, an allocating call of op, unless the allocation is
+// failed, and tracks the buffer it returns.
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) allocate(op string, fn func() (unsafe.Pointer, error)) (unsafe.Pointer, error) {
+	if err := c.before(op); err != nil {
+		return nil, err
+	}
+	if err := c.allocation(op); err != nil {
+		return nil, err
+	}
+
+	ptr, err := fn()
+	if err != nil {
+		return nil, err
+	}
+	c.track(op, ptr)
+
+	return ptr, nil
+}
+
+// corrupt marks element index of ptr as corrupted when a Corruption fault of
+// op fires.
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) corrupt(op string, ptr unsafe.Pointer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if f, ok := c.fireLocked(Corruption, op); ok {
+		c.corrupted[ptr] = f.Index
+	}
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) ID() ecc.ID {
+	return c.inner.ID()
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) CopyScalarsToDevice(scalars []Fr) (unsafe.Pointer, error) {
+	return c.allocate(CopyScalarsToDevice, func() (unsafe.Pointer, error) { return c.inner.CopyScalarsToDevice(scalars) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) CopyG1PointsToDevice(points []G1Affine) (unsafe.Pointer, error) {
+	return c.allocate(CopyG1PointsToDevice, func() (unsafe.Pointer, error) { return c.inner.CopyG1PointsToDevice(points) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) CopyG2PointsToDevice(points []G2Affine) (unsafe.Pointer, error) {
+	return c.allocate(CopyG2PointsToDevice, func() (unsafe.Pointer, error) { return c.inner.CopyG2PointsToDevice(points) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) CopyScalarsFromDevice(scalars_d unsafe.Pointer, size int) ([]Fr, error) {
+	if err := c.before(CopyScalarsFromDevice); err != nil {
+		return nil, err
+	}
+
+	scalars, err := c.inner.CopyScalarsFromDevice(scalars_d, size)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	index, ok := c.corrupted[scalars_d]
+	if f, fired := c.fireLocked(Corruption, CopyScalarsFromDevice); fired {
+		index, ok = f.Index, true
+	}
+	c.mu.Unlock()
+
+	if ok && index < len(scalars) {
+		var zero Fr
+		scalars[index] = zero
+	}
+
+	return scalars, nil
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) FreeDevicePointer(ptr unsafe.Pointer) {
+	c.mu.Lock()
+	if c.untrackLocked(ptr) {
+		delete(c.corrupted, ptr)
+	}
+	c.mu.Unlock()
+
+	c.inner.FreeDevicePointer(ptr)
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) MsmOnDevice(scalars_d, points_d unsafe.Pointer, count int) (G1Jac, error) {
+	var zero G1Jac
+	if err := c.before(MsmOnDevice); err != nil {
+		return zero, err
+	}
+
+	res, err := c.inner.MsmOnDevice(scalars_d, points_d, count)
+	if _, ok := c.fire(Corruption, MsmOnDevice); ok && err == nil {
+		return zero, nil
+	}
+
+	return res, err
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) MsmG2OnDevice(scalars_d, points_d unsafe.Pointer, count int) (G2Jac, error) {
+	var zero G2Jac
+	if err := c.before(MsmG2OnDevice); err != nil {
+		return zero, err
+	}
+
+	res, err := c.inner.MsmG2OnDevice(scalars_d, points_d, count)
+	if _, ok := c.fire(Corruption, MsmG2OnDevice); ok && err == nil {
+		return zero, nil
+	}
+
+	return res, err
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) GenerateTwiddleFactors(size int, inverse bool) (unsafe.Pointer, error) {
+	return c.allocate(GenerateTwiddleFactors, func() (unsafe.Pointer, error) { return c.inner.GenerateTwiddleFactors(size, inverse) })
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) NttOnDevice(scalars_out, scalars_d, twiddles_d, coset_powers_d unsafe.Pointer, size, twid_size int, isCoset bool) error {
+	if err := c.before(NttOnDevice); err != nil {
+		return err
+	}
+
+	if err := c.inner.NttOnDevice(scalars_out, scalars_d, twiddles_d, coset_powers_d, size, twid_size, isCoset); err != nil {
+		return err
+	}
+	c.corrupt(NttOnDevice, scalars_out)
+
+	return nil
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) INttOnDevice(scalars_d, twiddles_d, cosetPowers_d unsafe.Pointer, size int, isCoset bool) (unsafe.Pointer, error) {
+	out_d, err := c.allocate(INttOnDevice, func() (unsafe.Pointer, error) {
+		return c.inner.INttOnDevice(scalars_d, twiddles_d, cosetPowers_d, size, isCoset)
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.corrupt(INttOnDevice, out_d)
+
+	return out_d, nil
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) ReverseScalars(ptr unsafe.Pointer, size int) error {
+	if err := c.before(ReverseScalars); err != nil {
+		return err
+	}
+
+	return c.inner.ReverseScalars(ptr, size)
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) PolyOps(a_d, b_d, c_d, den_d unsafe.Pointer, size int) error {
+	if err := c.before(PolyOps); err != nil {
+		return err
+	}
+
+	if err := c.inner.PolyOps(a_d, b_d, c_d, den_d, size); err != nil {
+		return err
+	}
+	c.corrupt(PolyOps, a_d)
+
+	return nil
+}
+
+func (c *Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) MontConvOnDevice(scalars_d unsafe.Pointer, size int, is_into bool) error {
+	if err := c.before(MontConvOnDevice); err != nil {
+		return err
+	}
+
+	return c.inner.MontConvOnDevice(scalars_d, size, is_into)
+}
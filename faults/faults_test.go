@@ -0,0 +1,103 @@
+package faults
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark"
+	"github.com/ingonyama-zk/iciclegnark/internal/hostcurve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailAllocation(t *testing.T) {
+	host := hostcurve.NewInts(ecc.SECP256K1)
+	c := Wrap[int, int, int, int, int](host)
+
+	res, err := iciclegnark.Commit[int, int, int, int, int](c, []int{1, 2, 3}, []int{4, 5, 6})
+	assert.NoError(t, err)
+	assert.Equal(t, 32, res)
+
+	// the points fail to upload, the scalars are freed
+	c.Inject(FailAllocation(2))
+	_, err = iciclegnark.Commit[int, int, int, int, int](c, []int{1, 2, 3}, []int{4, 5, 6})
+	assert.True(t, errors.Is(err, ErrInjected))
+	var allocation *AllocationError
+	assert.True(t, errors.As(err, &allocation))
+	assert.Equal(t, CopyG1PointsToDevice, allocation.Op)
+	assert.Equal(t, 4, allocation.N)
+	assert.Zero(t, c.Live())
+	assert.Zero(t, host.Live())
+
+	// a fault on the nth call fires once
+	_, err = iciclegnark.Commit[int, int, int, int, int](c, []int{1}, []int{1})
+	assert.NoError(t, err)
+	assert.Zero(t, c.BadFrees())
+}
+
+func TestFailKernel(t *testing.T) {
+	c := Wrap[int, int, int, int, int](hostcurve.NewInts(ecc.SECP256K1))
+	c.Inject(FailKernel(MsmOnDevice, 7))
+
+	_, err := iciclegnark.Commit[int, int, int, int, int](c, []int{1, 2, 3}, []int{4, 5, 6})
+	assert.EqualError(t, err, "faults: MsmOnDevice returned status 7")
+	assert.True(t, errors.Is(err, ErrInjected))
+	assert.Zero(t, c.Live())
+
+	c.Reset()
+	_, err = iciclegnark.Commit[int, int, int, int, int](c, []int{1, 2, 3}, []int{4, 5, 6})
+	assert.NoError(t, err)
+
+	c.Inject(FailKernel(INttOnDevice, 1))
+	_, err = c.INttOnDevice(nil, nil, nil, 0, false)
+	assert.True(t, errors.Is(err, ErrInjected))
+	assert.Zero(t, c.Live())
+}
+
+func TestCorrupt(t *testing.T) {
+	c := Wrap[int, int, int, int, int](hostcurve.NewInts(ecc.SECP256K1))
+	c.Inject(Corrupt(NttOnDevice, 2), Corrupt(MsmOnDevice, 0))
+
+	in_d, _ := c.CopyScalarsToDevice([]int{1, 2, 3, 4})
+	out_d, _ := c.CopyScalarsToDevice(make([]int, 4))
+	assert.NoError(t, c.NttOnDevice(out_d, in_d, nil, nil, 4, 4, false))
+
+	// the corrupted element reads back until the buffer is freed
+	for i := 0; i < 2; i++ {
+		out, err := c.CopyScalarsFromDevice(out_d, 4)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 3, 0, 10}, out)
+	}
+	in, _ := c.CopyScalarsFromDevice(in_d, 4)
+	assert.Equal(t, []int{1, 2, 3, 4}, in)
+
+	res, err := c.MsmOnDevice(in_d, in_d, 4)
+	assert.NoError(t, err)
+	assert.Zero(t, res)
+
+	c.FreeDevicePointer(in_d)
+	c.FreeDevicePointer(out_d)
+	assert.Zero(t, c.Live())
+}
+
+func TestDelayCopy(t *testing.T) {
+	c := Wrap[int, int, int, int, int](hostcurve.NewInts(ecc.SECP256K1))
+	c.Inject(DelayCopy(CopyScalarsToDevice, 20*time.Millisecond))
+
+	start := time.Now()
+	ptr, err := c.CopyScalarsToDevice([]int{1})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	c.FreeDevicePointer(ptr)
+}
+
+func TestBadFrees(t *testing.T) {
+	c := Wrap[int, int, int, int, int](hostcurve.NewInts(ecc.SECP256K1))
+
+	ptr, _ := c.CopyScalarsToDevice([]int{1})
+	c.FreeDevicePointer(ptr)
+	c.FreeDevicePointer(ptr)
+	c.FreeDevicePointer(nil)
+	assert.Equal(t, 1, c.BadFrees())
+}
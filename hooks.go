@@ -0,0 +1,57 @@
+package iciclegnark
+
+import (
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// DeviceHooks intercepts the device calls of the curve packages: their
+// allocations and frees, copies to the device and kernels, and the device
+// selections of package devicectx. The hooks run
+// below the error handling of the packages, so that tests can fail a call
+// and check the package cleans up after it, see package faults.
+// Implementations must be safe for concurrent use.
+type DeviceHooks interface {
+	// Allocate runs before op allocates bytes on the device. An error fails
+	// the allocation without running it.
+	Allocate(curve ecc.ID, op string, bytes int) error
+	// Allocated reports the buffer an allocation of op returned.
+	Allocated(curve ecc.ID, op string, ptr unsafe.Pointer)
+	// Free reports a buffer about to be freed.
+	Free(curve ecc.ID, ptr unsafe.Pointer)
+	// Kernel runs before kernel op. A non-zero status fails the kernel
+	// without running it.
+	Kernel(curve ecc.ID, op string) int
+	// Corrupt runs after kernel op wrote its output. If ok is set, the
+	// element index of the output is zeroed on the device.
+	Corrupt(curve ecc.ID, op string) (index int, ok bool)
+	// SetDevice runs before a thread selects the CUDA device. An error fails
+	// the selection without running it.
+	SetDevice(device int) error
+}
+
+type hooksHolder struct{ DeviceHooks }
+
+var deviceHooks atomic.Pointer[hooksHolder]
+
+// SetDeviceHooks installs h for all curves, replacing the previous hooks. A
+// nil h removes them, which is the default.
+func SetDeviceHooks(h DeviceHooks) {
+	if h == nil {
+		deviceHooks.Store(nil)
+		return
+	}
+
+	deviceHooks.Store(&hooksHolder{h})
+}
+
+// CurrentDeviceHooks returns the hooks installed with SetDeviceHooks, or nil.
+func CurrentDeviceHooks() DeviceHooks {
+	if h := deviceHooks.Load(); h != nil {
+		return h.DeviceHooks
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package iciclegnark
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/assert"
+)
+
+type nopHooks struct{}
+
+func (nopHooks) Allocate(ecc.ID, string, int) error          { return nil }
+func (nopHooks) Allocated(ecc.ID, string, unsafe.Pointer)    {}
+func (nopHooks) Free(ecc.ID, unsafe.Pointer)                 {}
+func (nopHooks) Kernel(ecc.ID, string) int                   { return 0 }
+func (nopHooks) Corrupt(ecc.ID, string) (index int, ok bool) { return 0, false }
+func (nopHooks) SetDevice(int) error                         { return nil }
+
+func TestSetDeviceHooks(t *testing.T) {
+	assert.Nil(t, CurrentDeviceHooks())
+
+	SetDeviceHooks(nopHooks{})
+	defer SetDeviceHooks(nil)
+	assert.Equal(t, nopHooks{}, CurrentDeviceHooks())
+
+	SetDeviceHooks(nil)
+	assert.Nil(t, CurrentDeviceHooks())
+}
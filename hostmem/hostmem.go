@@ -0,0 +1,181 @@
+// Package hostmem provides host buffers uploads can read from directly and a
+// staging ring for chunked uploads.
+//
+// With the CUDA backend a HostBuffer is page-locked (cudaMallocHost), so
+// cudaMemcpy transfers from it by DMA instead of first copying pageable Go
+// memory into a driver staging buffer. Built with the cpu tag, buffers are
+// plain Go memory and "device" pointers are host memory, so the ring logic
+// runs without a GPU.
+package hostmem
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// HostBuffer is size bytes of host memory, page-locked when the backend allows.
+type HostBuffer struct {
+	ptr    unsafe.Pointer
+	size   int
+	pinned bool
+}
+
+// NewHostBuffer allocates size bytes. Free must be called to release them.
+func NewHostBuffer(size int) (*HostBuffer, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("hostmem: buffer size %d", size)
+	}
+
+	ptr, pinned, err := allocHost(size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HostBuffer{ptr: ptr, size: size, pinned: pinned}, nil
+}
+
+// Pointer returns the start of the buffer.
+func (b *HostBuffer) Pointer() unsafe.Pointer { return b.ptr }
+
+// Len returns the size of the buffer in bytes.
+func (b *HostBuffer) Len() int { return b.size }
+
+// Pinned reports whether the buffer is page-locked.
+func (b *HostBuffer) Pinned() bool { return b.pinned }
+
+// Bytes returns the buffer as a byte slice.
+func (b *HostBuffer) Bytes() []byte {
+	return unsafe.Slice((*byte)(b.ptr), b.size)
+}
+
+// Free releases the buffer. The buffer must not be used afterwards.
+func (b *HostBuffer) Free() error {
+	if b.ptr == nil {
+		return nil
+	}
+
+	err := freeHost(b.ptr, b.pinned)
+	b.ptr, b.size = nil, 0
+
+	return err
+}
+
+// Slice views the buffer as the largest []T it holds, so conversion
+// functions can write device-layout elements straight into it.
+func Slice[T any](b *HostBuffer) []T {
+	var zero T
+	return unsafe.Slice((*T)(b.ptr), b.size/int(unsafe.Sizeof(zero)))
+}
+
+// CopyToDevice copies size bytes from src to the device pointer dst_d.
+func CopyToDevice(dst_d unsafe.Pointer, src *HostBuffer, size int) error {
+	if size > src.size {
+		return fmt.Errorf("hostmem: copy of %d bytes from a %d byte buffer", size, src.size)
+	}
+
+	return memcpyHtoD(dst_d, src.ptr, size)
+}
+
+// Ring is a fixed set of staging buffers. An upload fills one buffer while
+// the previous ones are being copied to the device.
+type Ring struct {
+	buffers []*HostBuffer
+	chunk   int
+}
+
+// NewRing allocates slots staging buffers of chunkBytes each.
+func NewRing(slots, chunkBytes int) (*Ring, error) {
+	if slots < 1 {
+		return nil, fmt.Errorf("hostmem: ring of %d slots", slots)
+	}
+
+	r := &Ring{buffers: make([]*HostBuffer, 0, slots), chunk: chunkBytes}
+	for i := 0; i < slots; i++ {
+		b, err := NewHostBuffer(chunkBytes)
+		if err != nil {
+			r.Free()
+			return nil, err
+		}
+		r.buffers = append(r.buffers, b)
+	}
+
+	return r, nil
+}
+
+// ChunkBytes returns the size of one staging buffer.
+func (r *Ring) ChunkBytes() int { return r.chunk }
+
+// Upload copies total bytes to dst_d in chunks of at most ChunkBytes. fill
+// writes the bytes [offset, offset+len(chunk)) of the upload into chunk.
+// Upload returns after every chunk has reached the device.
+func (r *Ring) Upload(dst_d unsafe.Pointer, total int, fill func(chunk []byte, offset int) error) error {
+	return r.upload(dst_d, total, r.chunk, fill)
+}
+
+// UploadElements uploads n elements of type T to dst_d, filling each staging
+// buffer with as many whole elements as fit. convert writes the elements
+// [start, start+len(dst)) into dst.
+func UploadElements[T any](r *Ring, dst_d unsafe.Pointer, n int, convert func(dst []T, start int) error) error {
+	var zero T
+	size := int(unsafe.Sizeof(zero))
+	perChunk := r.chunk / size
+	if perChunk == 0 {
+		return fmt.Errorf("hostmem: %d byte staging buffers cannot hold a %d byte element", r.chunk, size)
+	}
+
+	return r.upload(dst_d, n*size, perChunk*size, func(chunk []byte, offset int) error {
+		return convert(unsafe.Slice((*T)(unsafe.Pointer(&chunk[0])), len(chunk)/size), offset/size)
+	})
+}
+
+func (r *Ring) upload(dst_d unsafe.Pointer, total, chunk int, fill func(chunk []byte, offset int) error) error {
+	type job struct {
+		slot, offset, size int
+	}
+
+	free := make(chan int, len(r.buffers))
+	for i := range r.buffers {
+		free <- i
+	}
+
+	jobs := make(chan job, len(r.buffers))
+	var copyErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := range jobs {
+			if copyErr == nil {
+				copyErr = memcpyHtoD(unsafe.Add(dst_d, j.offset), r.buffers[j.slot].ptr, j.size)
+			}
+			free <- j.slot
+		}
+	}()
+
+	var fillErr error
+	for offset := 0; offset < total && fillErr == nil; offset += chunk {
+		size := min(chunk, total-offset)
+		slot := <-free
+
+		if fillErr = fill(r.buffers[slot].Bytes()[:size], offset); fillErr == nil {
+			jobs <- job{slot, offset, size}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errors.Join(fillErr, copyErr)
+}
+
+// Free releases the staging buffers.
+func (r *Ring) Free() error {
+	var errs []error
+	for _, b := range r.buffers {
+		errs = append(errs, b.Free())
+	}
+	r.buffers = nil
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,25 @@
+package hostmem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHostBuffer(t *testing.T) {
+	b, err := NewHostBuffer(100)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, b.Len())
+	assert.Len(t, b.Bytes(), 100)
+
+	words := Slice[uint64](b)
+	assert.Len(t, words, 12)
+	words[1] = 0x0102030405060708
+	assert.Equal(t, byte(0x08), b.Bytes()[8])
+
+	assert.NoError(t, b.Free())
+	assert.NoError(t, b.Free())
+
+	_, err = NewHostBuffer(0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+//go:build cpu
+
+package hostmem
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// allocated keeps plain buffers reachable until they are freed, since their
+// only other reference is an unsafe.Pointer.
+var allocated sync.Map
+
+func allocHost(size int) (unsafe.Pointer, bool, error) {
+	buf := make([]byte, size)
+	ptr := unsafe.Pointer(&buf[0])
+	allocated.Store(ptr, buf)
+
+	return ptr, false, nil
+}
+
+func freeHost(ptr unsafe.Pointer, pinned bool) error {
+	allocated.Delete(ptr)
+	return nil
+}
+
+// memcpyHtoD copies between host memory: without a GPU, device pointers are
+// host memory too.
+func memcpyHtoD(dst_d, src unsafe.Pointer, size int) error {
+	copy(unsafe.Slice((*byte)(dst_d), size), unsafe.Slice((*byte)(src), size))
+	return nil
+}
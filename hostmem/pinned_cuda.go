@@ -0,0 +1,40 @@
+//go:build !cpu
+
+package hostmem
+
+// #cgo CFLAGS: -I /usr/local/cuda/include
+// #cgo LDFLAGS: -L/usr/local/cuda/lib64 -lcudart
+/*
+#include <cuda_runtime.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+func allocHost(size int) (unsafe.Pointer, bool, error) {
+	var ptr unsafe.Pointer
+	if err := C.cudaMallocHost(&ptr, C.size_t(size)); err != 0 {
+		return nil, false, fmt.Errorf("hostmem: cudaMallocHost of %d bytes failed with code %d", size, int(err))
+	}
+
+	return ptr, true, nil
+}
+
+func freeHost(ptr unsafe.Pointer, pinned bool) error {
+	if err := C.cudaFreeHost(ptr); err != 0 {
+		return fmt.Errorf("hostmem: cudaFreeHost failed with code %d", int(err))
+	}
+
+	return nil
+}
+
+func memcpyHtoD(dst_d, src unsafe.Pointer, size int) error {
+	if err := C.cudaMemcpy(dst_d, src, C.size_t(size), C.cudaMemcpyHostToDevice); err != 0 {
+		return fmt.Errorf("hostmem: cudaMemcpy of %d bytes failed with code %d", size, int(err))
+	}
+
+	return nil
+}
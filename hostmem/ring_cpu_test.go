@@ -0,0 +1,104 @@
+//go:build cpu
+
+package hostmem
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingUpload(t *testing.T) {
+	src := make([]byte, 1000)
+	for i := range src {
+		src[i] = byte(i * 7)
+	}
+
+	for _, slots := range []int{1, 2, 4} {
+		r, err := NewRing(slots, 64)
+		assert.NoError(t, err)
+
+		dst := make([]byte, len(src))
+		var offsets []int
+		err = r.Upload(unsafe.Pointer(&dst[0]), len(src), func(chunk []byte, offset int) error {
+			offsets = append(offsets, offset)
+			copy(chunk, src[offset:])
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, src, dst)
+		assert.Len(t, offsets, 16)
+		assert.Equal(t, 960, offsets[15])
+
+		assert.NoError(t, r.Free())
+	}
+}
+
+func TestRingUploadFillError(t *testing.T) {
+	r, err := NewRing(2, 8)
+	assert.NoError(t, err)
+	defer r.Free()
+
+	failure := errors.New("conversion failed")
+	dst := make([]byte, 64)
+	calls := 0
+	err = r.Upload(unsafe.Pointer(&dst[0]), len(dst), func(chunk []byte, offset int) error {
+		calls++
+		if offset == 16 {
+			return failure
+		}
+		for i := range chunk {
+			chunk[i] = 1
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, byte(1), dst[15])
+	assert.Equal(t, byte(0), dst[16])
+}
+
+func TestCopyToDevice(t *testing.T) {
+	b, err := NewHostBuffer(16)
+	assert.NoError(t, err)
+	defer b.Free()
+	assert.False(t, b.Pinned())
+
+	copy(b.Bytes(), "pinned host data")
+	dst := make([]byte, 16)
+	assert.NoError(t, CopyToDevice(unsafe.Pointer(&dst[0]), b, 16))
+	assert.Equal(t, "pinned host data", string(dst))
+
+	assert.Error(t, CopyToDevice(unsafe.Pointer(&dst[0]), b, 17))
+}
+
+func TestUploadElements(t *testing.T) {
+	type element [3]uint64
+
+	// 64 byte buffers hold two 24 byte elements
+	r, err := NewRing(2, 64)
+	assert.NoError(t, err)
+	defer r.Free()
+
+	dst := make([]element, 7)
+	var starts []int
+	err = UploadElements(r, unsafe.Pointer(&dst[0]), len(dst), func(chunk []element, start int) error {
+		starts = append(starts, start)
+		for i := range chunk {
+			chunk[i] = element{uint64(start + i), 1, 2}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 2, 4, 6}, starts)
+	for i, e := range dst {
+		assert.Equal(t, element{uint64(i), 1, 2}, e)
+	}
+
+	small, err := NewRing(1, 16)
+	assert.NoError(t, err)
+	defer small.Free()
+	assert.Error(t, UploadElements(small, unsafe.Pointer(&dst[0]), 1, func([]element, int) error { return nil }))
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"path"
+)
+
+type Curve struct {
+	Package         string // package name under curves/ and in goicicle/curves
+	Name            string // upper case name used in test names
+	GnarkPackage    string // gnark-crypto import path
+	EccID           string // ecc.ID constant of the curve
+	FrLimbs         int    // 64 bit limbs of the scalar field
+	FpLimbs         int    // 64 bit limbs of the base field
+	G2Degree        int    // degree over Fp of the field holding G2 coordinates
+	ScalarConverter string // converts fr.Element.Bits() to icicle uint32 limbs
+	BaseConverter   string // converts fp.Element.Bits() to icicle uint32 limbs
+	LocalConverters bool   // the converters are not provided by icicle and are generated
+}
+
+var curves = []Curve{
+	{
+		Package:         "bn254",
+		Name:            "BN254",
+		GnarkPackage:    "github.com/consensys/gnark-crypto/ecc/bn254",
+		EccID:           "BN254",
+		FrLimbs:         4,
+		FpLimbs:         4,
+		G2Degree:        2,
+		ScalarConverter: "icicle.ConvertUint64ArrToUint32Arr",
+		BaseConverter:   "icicle.ConvertUint64ArrToUint32Arr",
+	},
+	{
+		Package:         "bls12377",
+		Name:            "BLS12377",
+		GnarkPackage:    "github.com/consensys/gnark-crypto/ecc/bls12-377",
+		EccID:           "BLS12_377",
+		FrLimbs:         4,
+		FpLimbs:         6,
+		G2Degree:        2,
+		ScalarConverter: "icicle.ConvertUint64ArrToUint32Arr4",
+		BaseConverter:   "icicle.ConvertUint64ArrToUint32Arr6",
+	},
+	{
+		Package:         "bls12381",
+		Name:            "BLS12381",
+		GnarkPackage:    "github.com/consensys/gnark-crypto/ecc/bls12-381",
+		EccID:           "BLS12_381",
+		FrLimbs:         4,
+		FpLimbs:         6,
+		G2Degree:        2,
+		ScalarConverter: "icicle.ConvertUint64ArrToUint32Arr4",
+		BaseConverter:   "icicle.ConvertUint64ArrToUint32Arr6",
+	},
+	{
+		Package:         "bw6761",
+		Name:            "BW6761",
+		GnarkPackage:    "github.com/consensys/gnark-crypto/ecc/bw6-761",
+		EccID:           "BW6_761",
+		FrLimbs:         6,
+		FpLimbs:         12,
+		G2Degree:        1,
+		ScalarConverter: "ConvertUint64ArrToUint32Arr6",
+		BaseConverter:   "ConvertUint64ArrToUint32Arr12",
+		LocalConverters: true,
+	},
+}
+
+func (c Curve) FrBytes() int { return c.FrLimbs * 8 }
+
+func (c Curve) FpBytes() int { return c.FpLimbs * 8 }
+
+// GnarkImport is the import spec of the gnark-crypto curve package, aliased
+// when the directory name differs from the package name.
+func (c Curve) GnarkImport() string {
+	if path.Base(c.GnarkPackage) == c.Package {
+		return fmt.Sprintf("%q", c.GnarkPackage)
+	}
+
+	return fmt.Sprintf("%s %q", c.Package, c.GnarkPackage)
+}
+
+func (c Curve) IcicleImport() string {
+	return fmt.Sprintf("icicle %q", "github.com/ingonyama-zk/icicle/goicicle/curves/"+c.Package)
+}
+
+// GenericFields is set when the scalar and base fields share a limb layout, in
+// which case the field constructors are generic over both icicle field types.
+func (c Curve) GenericFields() bool { return c.FrLimbs == c.FpLimbs }
+
+func (c Curve) ScalarCtor() string {
+	return c.instantiate("NewFieldFromFrGnark", "icicle.G1ScalarField")
+}
+
+func (c Curve) BaseCtor() string { return c.instantiate("NewFieldFromFpGnark", "icicle.G1BaseField") }
+
+func (c Curve) BatchScalarCtor() string {
+	return c.instantiate("BatchConvertFromFrGnark", "icicle.G1ScalarField")
+}
+
+func (c Curve) BatchScalarCtorThreaded() string {
+	return c.instantiate("BatchConvertFromFrGnarkThreaded", "icicle.G1ScalarField")
+}
+
+func (c Curve) instantiate(fn, typ string) string {
+	if c.GenericFields() {
+		return fn + "[" + typ + "]"
+	}
+
+	return fn
+}
+
+// FieldTypeParam is the type parameter list of the field constructors.
+func (c Curve) FieldTypeParam() string {
+	if c.GenericFields() {
+		return "[T icicle.G1BaseField | icicle.G1ScalarField]"
+	}
+
+	return ""
+}
+
+func (c Curve) ScalarType() string {
+	if c.GenericFields() {
+		return "T"
+	}
+
+	return "icicle.G1ScalarField"
+}
+
+func (c Curve) BaseType() string {
+	if c.GenericFields() {
+		return "T"
+	}
+
+	return "icicle.G1BaseField"
+}
+
+// ScalarCtorT calls NewFieldFromFrGnark from inside a function sharing its type parameter.
+func (c Curve) ScalarCtorT() string { return c.instantiate("NewFieldFromFrGnark", "T") }
@@ -0,0 +1,88 @@
+// Command generator renders the curve packages under curves/ from the
+// templates in this directory, one sub-package per entry in curves.
+//
+//	go generate ./curves
+package main
+
+import (
+	"bytes"
+	"embed"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+const header = `// Copyright 2023 Ingonyama
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by Ingonyama DO NOT EDIT
+
+`
+
+var funcs = template.FuncMap{
+	"list": func(v ...int) []int { return v },
+	"mul":  func(a, b int) int { return a * b },
+}
+
+func main() {
+	out := flag.String("out", "curves", "directory holding one sub-directory per curve")
+	flag.Parse()
+
+	tmpl := template.Must(template.New("").Funcs(funcs).ParseFS(templates, "templates/*.tmpl"))
+
+	for _, c := range curves {
+		if err := generate(tmpl, *out, c); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func generate(tmpl *template.Template, out string, c Curve) error {
+	dir := filepath.Join(out, c.Package)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, t := range tmpl.Templates() {
+		if !strings.HasSuffix(t.Name(), ".go.tmpl") {
+			continue
+		}
+
+		var buf bytes.Buffer
+		buf.WriteString(header)
+		if err := t.Execute(&buf, c); err != nil {
+			return fmt.Errorf("%s: %w", c.Package, err)
+		}
+
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("%s/%s: %w", c.Package, t.Name(), err)
+		}
+
+		file := filepath.Join(dir, strings.TrimSuffix(t.Name(), ".tmpl"))
+		if err := os.WriteFile(file, src, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
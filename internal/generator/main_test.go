@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedUpToDate(t *testing.T) {
+	tmpl := template.Must(template.New("").Funcs(funcs).ParseFS(templates, "templates/*.tmpl"))
+	out := t.TempDir()
+
+	for _, c := range curves {
+		assert.NoError(t, generate(tmpl, out, c))
+
+		files, err := os.ReadDir(filepath.Join(out, c.Package))
+		assert.NoError(t, err)
+
+		for _, f := range files {
+			generated, _ := os.ReadFile(filepath.Join(out, c.Package, f.Name()))
+			committed, err := os.ReadFile(filepath.Join("..", "..", "curves", c.Package, f.Name()))
+
+			assert.NoError(t, err)
+			assert.Equal(t, string(generated), string(committed), "curves/%s/%s is stale, run go generate ./curves", c.Package, f.Name())
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Package hostcurve implements the device API of iciclegnark.Curve on the
+// host, so that the generic code can be tested without a GPU. Device buffers
+// are host slices indexed by their first element's address.
+//
+// The package does not import iciclegnark, so that the tests of the root
+// package can use it.
+package hostcurve
+
+import (
+	"sync"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+)
+
+// Ints is a curve over integers, enough to follow data through a pipeline:
+// the G1 MSM is a dot product and the G2 MSM its negation, the NTT is a
+// prefix sum and the inverse NTT takes the differences back. It is safe for
+// concurrent use.
+type Ints struct {
+	id ecc.ID
+
+	mu      sync.Mutex
+	buffers map[unsafe.Pointer][]int
+	freed   int
+}
+
+// NewInts returns an integer curve reporting id.
+func NewInts(id ecc.ID) *Ints {
+	return &Ints{id: id, buffers: make(map[unsafe.Pointer][]int)}
+}
+
+// Upload stores a copy of v as a device buffer.
+func (c *Ints) Upload(v []int) unsafe.Pointer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	buf := append([]int{}, v...)
+	ptr := unsafe.Pointer(&buf[0])
+	c.buffers[ptr] = buf
+
+	return ptr
+}
+
+func (c *Ints) buffer(ptr unsafe.Pointer) []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.buffers[ptr]
+}
+
+// Live returns the number of buffers not freed yet.
+func (c *Ints) Live() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.buffers)
+}
+
+// Freed returns the number of calls to FreeDevicePointer.
+func (c *Ints) Freed() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.freed
+}
+
+func (c *Ints) ID() ecc.ID { return c.id }
+
+func (c *Ints) CopyScalarsToDevice(scalars []int) (unsafe.Pointer, error) {
+	return c.Upload(scalars), nil
+}
+
+func (c *Ints) CopyG1PointsToDevice(points []int) (unsafe.Pointer, error) {
+	return c.Upload(points), nil
+}
+
+func (c *Ints) CopyG2PointsToDevice(points []int) (unsafe.Pointer, error) {
+	return c.Upload(points), nil
+}
+
+func (c *Ints) CopyScalarsFromDevice(scalars_d unsafe.Pointer, size int) ([]int, error) {
+	return append([]int{}, c.buffer(scalars_d)[:size]...), nil
+}
+
+func (c *Ints) FreeDevicePointer(ptr unsafe.Pointer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.buffers, ptr)
+	c.freed++
+}
+
+func (c *Ints) MsmOnDevice(scalars_d, points_d unsafe.Pointer, count int) (int, error) {
+	scalars, points := c.buffer(scalars_d), c.buffer(points_d)
+
+	var res int
+	for i := 0; i < count; i++ {
+		res += scalars[i] * points[i]
+	}
+
+	return res, nil
+}
+
+func (c *Ints) MsmG2OnDevice(scalars_d, points_d unsafe.Pointer, count int) (int, error) {
+	res, err := c.MsmOnDevice(scalars_d, points_d, count)
+	return -res, err
+}
+
+func (c *Ints) GenerateTwiddleFactors(size int, inverse bool) (unsafe.Pointer, error) {
+	return c.Upload([]int{size}), nil
+}
+
+func (c *Ints) NttOnDevice(scalars_out, scalars_d, _, _ unsafe.Pointer, size, _ int, _ bool) error {
+	in, out := c.buffer(scalars_d), c.buffer(scalars_out)
+
+	sum := 0
+	for i := 0; i < size; i++ {
+		sum += in[i]
+		out[i] = sum
+	}
+
+	return nil
+}
+
+func (c *Ints) INttOnDevice(scalars_d, _, _ unsafe.Pointer, size int, _ bool) (unsafe.Pointer, error) {
+	in := c.buffer(scalars_d)
+
+	out := make([]int, size)
+	for i := range out {
+		out[i] = in[i]
+		if i > 0 {
+			out[i] -= in[i-1]
+		}
+	}
+
+	return c.Upload(out), nil
+}
+
+func (c *Ints) ReverseScalars(unsafe.Pointer, int) error { return nil }
+
+func (c *Ints) PolyOps(a_d, b_d, c_d, den_d unsafe.Pointer, size int) error {
+	a, b, cc, den := c.buffer(a_d), c.buffer(b_d), c.buffer(c_d), c.buffer(den_d)
+	for i := 0; i < size; i++ {
+		a[i] = (a[i]*b[i] - cc[i]) * den[i]
+	}
+
+	return nil
+}
+
+func (c *Ints) MontConvOnDevice(unsafe.Pointer, int, bool) error { return nil }
+
+// BN254 is a bn254 curve computing with gnark-crypto, laying buffers out the
+// way the icicle wrappers do. Like a device it keeps its state without
+// synchronization, so concurrent calls must be serialized by the caller.
+type BN254 struct {
+	buffers map[unsafe.Pointer]any
+}
+
+// NewBN254 returns a host bn254 curve.
+func NewBN254() *BN254 {
+	return &BN254{buffers: make(map[unsafe.Pointer]any)}
+}
+
+func (c *BN254) store(ptr unsafe.Pointer, v any) unsafe.Pointer {
+	c.buffers[ptr] = v
+	return ptr
+}
+
+func (c *BN254) scalars(ptr unsafe.Pointer) []fr.Element {
+	return c.buffers[ptr].([]fr.Element)
+}
+
+// Live returns the number of buffers not freed yet.
+func (c *BN254) Live() int { return len(c.buffers) }
+
+func (c *BN254) ID() ecc.ID { return ecc.BN254 }
+
+func (c *BN254) CopyScalarsToDevice(scalars []fr.Element) (unsafe.Pointer, error) {
+	buf := append([]fr.Element{}, scalars...)
+	return c.store(unsafe.Pointer(&buf[0]), buf), nil
+}
+
+func (c *BN254) CopyG1PointsToDevice(points []bn254.G1Affine) (unsafe.Pointer, error) {
+	buf := append([]bn254.G1Affine{}, points...)
+	return c.store(unsafe.Pointer(&buf[0]), buf), nil
+}
+
+func (c *BN254) CopyG2PointsToDevice(points []bn254.G2Affine) (unsafe.Pointer, error) {
+	buf := append([]bn254.G2Affine{}, points...)
+	return c.store(unsafe.Pointer(&buf[0]), buf), nil
+}
+
+func (c *BN254) CopyScalarsFromDevice(scalars_d unsafe.Pointer, size int) ([]fr.Element, error) {
+	return append([]fr.Element{}, c.scalars(scalars_d)[:size]...), nil
+}
+
+func (c *BN254) FreeDevicePointer(ptr unsafe.Pointer) {
+	delete(c.buffers, ptr)
+}
+
+func (c *BN254) MsmOnDevice(scalars_d, points_d unsafe.Pointer, count int) (bn254.G1Jac, error) {
+	var res bn254.G1Jac
+	_, err := res.MultiExp(c.buffers[points_d].([]bn254.G1Affine)[:count], c.scalars(scalars_d)[:count], ecc.MultiExpConfig{})
+
+	return res, err
+}
+
+func (c *BN254) MsmG2OnDevice(scalars_d, points_d unsafe.Pointer, count int) (bn254.G2Jac, error) {
+	var res bn254.G2Jac
+	_, err := res.MultiExp(c.buffers[points_d].([]bn254.G2Affine)[:count], c.scalars(scalars_d)[:count], ecc.MultiExpConfig{})
+
+	return res, err
+}
+
+func (c *BN254) GenerateTwiddleFactors(size int, inverse bool) (unsafe.Pointer, error) {
+	domain := fft.NewDomain(uint64(size))
+	return c.store(unsafe.Pointer(domain), domain), nil
+}
+
+// NttOnDevice scales by the coset powers and then transforms, like icicle
+func (c *BN254) NttOnDevice(scalars_out, scalars_d, twiddles_d, coset_powers_d unsafe.Pointer, size, twid_size int, isCoset bool) error {
+	out := c.scalars(scalars_out)
+	copy(out, c.scalars(scalars_d)[:size])
+	if isCoset {
+		powers := c.scalars(coset_powers_d)
+		for i := range out[:size] {
+			out[i].Mul(&out[i], &powers[i])
+		}
+	}
+
+	fft.NewDomain(uint64(size)).FFT(out[:size], fft.DIF)
+	fft.BitReverse(out[:size])
+
+	return nil
+}
+
+// INttOnDevice transforms and then scales by the inverse coset powers, like icicle
+func (c *BN254) INttOnDevice(scalars_d, twiddles_d, cosetPowers_d unsafe.Pointer, size int, isCoset bool) (unsafe.Pointer, error) {
+	out := append([]fr.Element{}, c.scalars(scalars_d)[:size]...)
+	fft.NewDomain(uint64(size)).FFTInverse(out, fft.DIF)
+	fft.BitReverse(out)
+
+	if isCoset {
+		powers := c.scalars(cosetPowers_d)
+		for i := range out {
+			out[i].Mul(&out[i], &powers[i])
+		}
+	}
+
+	return c.store(unsafe.Pointer(&out[0]), out), nil
+}
+
+func (c *BN254) ReverseScalars(ptr unsafe.Pointer, size int) error {
+	fft.BitReverse(c.scalars(ptr)[:size])
+	return nil
+}
+
+func (c *BN254) PolyOps(a_d, b_d, c_d, den_d unsafe.Pointer, size int) error {
+	a, b, cc, den := c.scalars(a_d), c.scalars(b_d), c.scalars(c_d), c.scalars(den_d)
+	for i := 0; i < size; i++ {
+		a[i].Mul(&a[i], &b[i]).Sub(&a[i], &cc[i]).Mul(&a[i], &den[i])
+	}
+
+	return nil
+}
+
+func (c *BN254) MontConvOnDevice(scalars_d unsafe.Pointer, size int, is_into bool) error {
+	return nil
+}
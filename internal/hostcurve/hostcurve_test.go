@@ -0,0 +1,68 @@
+package hostcurve_test
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/ingonyama-zk/iciclegnark"
+	"github.com/ingonyama-zk/iciclegnark/internal/hostcurve"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ iciclegnark.Curve[int, int, int, int, int]                                              = (*hostcurve.Ints)(nil)
+	_ iciclegnark.Curve[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac] = (*hostcurve.BN254)(nil)
+)
+
+func TestInts(t *testing.T) {
+	c := hostcurve.NewInts(ecc.BN254)
+
+	scalars_d, err := c.CopyScalarsToDevice([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+	out_d := c.Upload(make([]int, 4))
+	require.NoError(t, c.NttOnDevice(out_d, scalars_d, nil, nil, 4, 4, false))
+	evaluations, err := c.CopyScalarsFromDevice(out_d, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 3, 6, 10}, evaluations)
+
+	// the inverse NTT takes the prefix sum back
+	coefficients_d, err := c.INttOnDevice(out_d, nil, nil, 4, false)
+	require.NoError(t, err)
+	coefficients, err := c.CopyScalarsFromDevice(coefficients_d, 4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, coefficients)
+
+	c.FreeDevicePointer(scalars_d)
+	c.FreeDevicePointer(out_d)
+	c.FreeDevicePointer(coefficients_d)
+	assert.Zero(t, c.Live())
+	assert.Equal(t, 3, c.Freed())
+}
+
+func TestBN254(t *testing.T) {
+	c := hostcurve.NewBN254()
+
+	scalars := make([]fr.Element, 8)
+	for i := range scalars {
+		scalars[i].SetRandom()
+	}
+	scalars_d, err := c.CopyScalarsToDevice(scalars)
+	require.NoError(t, err)
+	out_d, err := c.CopyScalarsToDevice(make([]fr.Element, 8))
+	require.NoError(t, err)
+
+	require.NoError(t, c.NttOnDevice(out_d, scalars_d, nil, nil, 8, 8, false))
+	coefficients_d, err := c.INttOnDevice(out_d, nil, nil, 8, false)
+	require.NoError(t, err)
+	coefficients, err := c.CopyScalarsFromDevice(coefficients_d, 8)
+	require.NoError(t, err)
+	assert.Equal(t, scalars, coefficients)
+
+	c.FreeDevicePointer(scalars_d)
+	c.FreeDevicePointer(out_d)
+	c.FreeDevicePointer(coefficients_d)
+	assert.Zero(t, c.Live())
+}
@@ -0,0 +1,61 @@
+package iciclegnark
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+var logger atomic.Pointer[slog.Logger]
+
+// SetLogger routes the diagnostics of every curve package to l. A nil l
+// silences them again, which is the default.
+func SetLogger(l *slog.Logger) {
+	logger.Store(l)
+}
+
+// Logger returns the logger installed with SetLogger, or a logger discarding
+// everything.
+func Logger() *slog.Logger {
+	if l := logger.Load(); l != nil {
+		return l
+	}
+
+	return discard
+}
+
+var discard = slog.New(discardHandler{})
+
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// Options are the per-call settings of curve package functions.
+type Options struct {
+	Logger *slog.Logger
+}
+
+// Option overrides a per-call setting.
+type Option func(*Options)
+
+// WithLogger logs the diagnostics of one call to l instead of the package
+// logger.
+func WithLogger(l *slog.Logger) Option {
+	return func(o *Options) { o.Logger = l }
+}
+
+// ApplyOptions resolves opts over the package defaults.
+func ApplyOptions(opts ...Option) Options {
+	o := Options{Logger: Logger()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Logger == nil {
+		o.Logger = discard
+	}
+
+	return o
+}
@@ -0,0 +1,34 @@
+package iciclegnark
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerSilentByDefault(t *testing.T) {
+	assert.False(t, Logger().Enabled(context.Background(), slog.LevelError))
+	assert.False(t, ApplyOptions().Logger.Enabled(context.Background(), slog.LevelError))
+	assert.False(t, ApplyOptions(WithLogger(nil)).Logger.Enabled(context.Background(), slog.LevelError))
+}
+
+func TestSetLogger(t *testing.T) {
+	var global, call bytes.Buffer
+	SetLogger(slog.New(slog.NewTextHandler(&global, nil)))
+	defer SetLogger(nil)
+
+	ApplyOptions().Logger.Error("evaluate failed", slog.Int("code", 3))
+	assert.Contains(t, global.String(), "level=ERROR")
+	assert.Contains(t, global.String(), "code=3")
+
+	// a per-call logger overrides the package one
+	ApplyOptions(WithLogger(slog.New(slog.NewTextHandler(&call, nil)))).Logger.Error("vector operation failed")
+	assert.Contains(t, call.String(), "vector operation failed")
+	assert.NotContains(t, global.String(), "vector operation failed")
+
+	SetLogger(nil)
+	assert.False(t, Logger().Enabled(context.Background(), slog.LevelError))
+}
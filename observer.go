@@ -0,0 +1,78 @@
+package iciclegnark
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Operation describes one device operation or host conversion.
+type Operation struct {
+	Name  string
+	Curve ecc.ID
+	// Size is the number of elements the operation works on.
+	Size int
+	// Bytes is the number of bytes moved between host and device, zero for
+	// operations that stay on one side.
+	Bytes int
+}
+
+// Observer is notified around every operation of the curve packages.
+// Implementations must be safe for concurrent use.
+type Observer interface {
+	Started(op Operation)
+	Finished(op Operation, elapsed time.Duration, err error)
+}
+
+type observerHolder struct{ Observer }
+
+var observer atomic.Pointer[observerHolder]
+
+// SetObserver installs o for all curves, replacing the previous observer.
+// A nil o disables observation, which is the default.
+func SetObserver(o Observer) {
+	if o == nil {
+		observer.Store(nil)
+		return
+	}
+
+	observer.Store(&observerHolder{o})
+}
+
+func noop(error) {}
+
+// Observe reports op as started and returns the function reporting it as
+// finished. Curve packages call it as
+//
+//	defer iciclegnark.Observe(op)(nil)
+//
+// or keep the returned function to pass the error of the operation.
+func Observe(op Operation) func(err error) {
+	h := observer.Load()
+	if h == nil {
+		return noop
+	}
+
+	h.Started(op)
+	start := time.Now()
+
+	return func(err error) {
+		h.Finished(op, time.Since(start), err)
+	}
+}
+
+// Observers fans notifications out to every observer in order.
+type Observers []Observer
+
+func (o Observers) Started(op Operation) {
+	for _, observer := range o {
+		observer.Started(op)
+	}
+}
+
+func (o Observers) Finished(op Operation, elapsed time.Duration, err error) {
+	for _, observer := range o {
+		observer.Finished(op, elapsed, err)
+	}
+}
@@ -0,0 +1,156 @@
+package iciclegnark
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the duration histogram.
+var DefaultBuckets = []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5, 10}
+
+type metricKey struct {
+	op, curve string
+}
+
+type opMetrics struct {
+	ok, failed int64
+	bytes      int64
+	buckets    []int64 // cumulative counts, one per bound
+	sum        float64
+}
+
+// Metrics aggregates operations into counters and histograms and writes them
+// in the Prometheus text exposition format.
+type Metrics struct {
+	mu       sync.Mutex
+	buckets  []float64
+	ops      map[metricKey]*opMetrics
+	inFlight int64
+}
+
+// NewMetrics returns empty metrics using DefaultBuckets.
+func NewMetrics() *Metrics {
+	return &Metrics{buckets: DefaultBuckets, ops: make(map[metricKey]*opMetrics)}
+}
+
+func (m *Metrics) Started(op Operation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight++
+}
+
+func (m *Metrics) Finished(op Operation, elapsed time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight--
+
+	key := metricKey{op.Name, op.Curve.String()}
+	o, ok := m.ops[key]
+	if !ok {
+		o = &opMetrics{buckets: make([]int64, len(m.buckets))}
+		m.ops[key] = o
+	}
+
+	if err != nil {
+		o.failed++
+	} else {
+		o.ok++
+	}
+	o.bytes += int64(op.Bytes)
+
+	seconds := elapsed.Seconds()
+	o.sum += seconds
+	for i, bound := range m.buckets {
+		if seconds <= bound {
+			o.buckets[i]++
+		}
+	}
+}
+
+// WriteTo writes the metrics in the Prometheus text format, series sorted by
+// operation and curve.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]metricKey, 0, len(m.ops))
+	for k := range m.ops {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].op != keys[j].op {
+			return keys[i].op < keys[j].op
+		}
+		return keys[i].curve < keys[j].curve
+	})
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	fmt.Fprintln(cw, "# HELP iciclegnark_operations_total Operations finished, by status.")
+	fmt.Fprintln(cw, "# TYPE iciclegnark_operations_total counter")
+	for _, k := range keys {
+		o := m.ops[k]
+		fmt.Fprintf(cw, "iciclegnark_operations_total{op=%q,curve=%q,status=\"ok\"} %d\n", k.op, k.curve, o.ok)
+		fmt.Fprintf(cw, "iciclegnark_operations_total{op=%q,curve=%q,status=\"error\"} %d\n", k.op, k.curve, o.failed)
+	}
+
+	fmt.Fprintln(cw, "# HELP iciclegnark_operation_duration_seconds Duration of operations.")
+	fmt.Fprintln(cw, "# TYPE iciclegnark_operation_duration_seconds histogram")
+	for _, k := range keys {
+		o := m.ops[k]
+		for i, bound := range m.buckets {
+			fmt.Fprintf(cw, "iciclegnark_operation_duration_seconds_bucket{op=%q,curve=%q,le=\"%g\"} %d\n", k.op, k.curve, bound, o.buckets[i])
+		}
+		count := o.ok + o.failed
+		fmt.Fprintf(cw, "iciclegnark_operation_duration_seconds_bucket{op=%q,curve=%q,le=\"+Inf\"} %d\n", k.op, k.curve, count)
+		fmt.Fprintf(cw, "iciclegnark_operation_duration_seconds_sum{op=%q,curve=%q} %g\n", k.op, k.curve, o.sum)
+		fmt.Fprintf(cw, "iciclegnark_operation_duration_seconds_count{op=%q,curve=%q} %d\n", k.op, k.curve, count)
+	}
+
+	fmt.Fprintln(cw, "# HELP iciclegnark_transferred_bytes_total Bytes moved between host and device.")
+	fmt.Fprintln(cw, "# TYPE iciclegnark_transferred_bytes_total counter")
+	for _, k := range keys {
+		fmt.Fprintf(cw, "iciclegnark_transferred_bytes_total{op=%q,curve=%q} %d\n", k.op, k.curve, m.ops[k].bytes)
+	}
+
+	fmt.Fprintln(cw, "# HELP iciclegnark_operations_in_flight Operations started and not finished.")
+	fmt.Fprintln(cw, "# TYPE iciclegnark_operations_in_flight gauge")
+	fmt.Fprintf(cw, "iciclegnark_operations_in_flight %d\n", m.inFlight)
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+
+	return cw.n, cw.w.Flush()
+}
+
+// ServeHTTP serves the metrics for scraping.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteTo(w)
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+
+	return n, err
+}
@@ -0,0 +1,37 @@
+package iciclegnark
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SlogObserver logs every finished operation to a slog.Logger, at Debug level
+// on success and Error level on failure.
+type SlogObserver struct {
+	Logger *slog.Logger
+}
+
+// NewSlogObserver returns an observer logging to logger.
+func NewSlogObserver(logger *slog.Logger) *SlogObserver {
+	return &SlogObserver{Logger: logger}
+}
+
+func (o *SlogObserver) Started(op Operation) {}
+
+func (o *SlogObserver) Finished(op Operation, elapsed time.Duration, err error) {
+	level := slog.LevelDebug
+	attrs := []slog.Attr{
+		slog.String("op", op.Name),
+		slog.String("curve", op.Curve.String()),
+		slog.Int("size", op.Size),
+		slog.Int("bytes", op.Bytes),
+		slog.Duration("elapsed", elapsed),
+	}
+	if err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	o.Logger.LogAttrs(context.Background(), level, "iciclegnark operation", attrs...)
+}
@@ -0,0 +1,128 @@
+package iciclegnark
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	mu       sync.Mutex
+	started  []Operation
+	finished []error
+}
+
+func (r *recorder) Started(op Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.started = append(r.started, op)
+}
+
+func (r *recorder) Finished(op Operation, elapsed time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.finished = append(r.finished, err)
+}
+
+func TestObserve(t *testing.T) {
+	op := Operation{Name: "MsmOnDevice", Curve: ecc.BN254, Size: 8}
+
+	// no observer installed
+	Observe(op)(nil)
+
+	r := &recorder{}
+	SetObserver(r)
+	defer SetObserver(nil)
+
+	failure := errors.New("failed")
+	done := Observe(op)
+	assert.Equal(t, []Operation{op}, r.started)
+	assert.Empty(t, r.finished)
+	done(failure)
+	assert.Equal(t, []error{failure}, r.finished)
+
+	SetObserver(nil)
+	Observe(op)(nil)
+	assert.Len(t, r.started, 1)
+}
+
+func TestObservers(t *testing.T) {
+	a, b := &recorder{}, &recorder{}
+	SetObserver(Observers{a, b})
+	defer SetObserver(nil)
+
+	Observe(Operation{Name: "PolyOps"})(nil)
+	assert.Len(t, a.started, 1)
+	assert.Len(t, b.finished, 1)
+}
+
+func TestSlogObserver(t *testing.T) {
+	var buf bytes.Buffer
+	o := NewSlogObserver(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	op := Operation{Name: "CopyToDevice", Curve: ecc.BLS12_377, Size: 4, Bytes: 128}
+	o.Finished(op, time.Millisecond, nil)
+	o.Finished(op, time.Millisecond, errors.New("out of memory"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "level=DEBUG")
+	assert.Contains(t, lines[0], "op=CopyToDevice curve=bls12_377 size=4 bytes=128 elapsed=1ms")
+	assert.Contains(t, lines[1], "level=ERROR")
+	assert.Contains(t, lines[1], `error="out of memory"`)
+}
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+	msm := Operation{Name: "MsmOnDevice", Curve: ecc.BN254, Size: 16}
+	cp := Operation{Name: "CopyToDevice", Curve: ecc.BN254, Size: 16, Bytes: 512}
+
+	m.Started(msm)
+	m.Finished(msm, 2*time.Millisecond, nil)
+	m.Started(msm)
+	m.Finished(msm, 2*time.Second, errors.New("failed"))
+	m.Started(cp)
+	m.Finished(cp, 50*time.Microsecond, nil)
+	m.Started(cp)
+
+	var buf bytes.Buffer
+	n, err := m.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE iciclegnark_operations_total counter",
+		`iciclegnark_operations_total{op="MsmOnDevice",curve="bn254",status="ok"} 1`,
+		`iciclegnark_operations_total{op="MsmOnDevice",curve="bn254",status="error"} 1`,
+		"# TYPE iciclegnark_operation_duration_seconds histogram",
+		`iciclegnark_operation_duration_seconds_bucket{op="MsmOnDevice",curve="bn254",le="0.001"} 0`,
+		`iciclegnark_operation_duration_seconds_bucket{op="MsmOnDevice",curve="bn254",le="0.005"} 1`,
+		`iciclegnark_operation_duration_seconds_bucket{op="MsmOnDevice",curve="bn254",le="5"} 2`,
+		`iciclegnark_operation_duration_seconds_bucket{op="MsmOnDevice",curve="bn254",le="+Inf"} 2`,
+		`iciclegnark_operation_duration_seconds_sum{op="MsmOnDevice",curve="bn254"} 2.002`,
+		`iciclegnark_operation_duration_seconds_count{op="MsmOnDevice",curve="bn254"} 2`,
+		`iciclegnark_transferred_bytes_total{op="CopyToDevice",curve="bn254"} 512`,
+		"iciclegnark_operations_in_flight 1",
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+
+	// series are sorted by operation
+	assert.Less(t, strings.Index(out, `op="CopyToDevice"`), strings.Index(out, `op="MsmOnDevice"`))
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, out, rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+}
@@ -0,0 +1,345 @@
+// Package planner estimates the device memory a Groth16 proof needs and
+// decides which proving key buffers stay resident on the device and which
+// are streamed in for the stage that uses them.
+//
+// A proof runs these stages one after the other:
+//
+//	ntt     compute H: a, b, c, the quotient denominator, twiddles, coset powers and NTT scratch
+//	msm_a   G1 MSM of the wires with pk.G1.A
+//	msm_b1  G1 MSM of the wires with pk.G1.B
+//	msm_b2  G2 MSM of the wires with pk.G2.B
+//	msm_k   G1 MSM of the private wires with pk.G1.K
+//	msm_z   G1 MSM of H with pk.G1.Z
+//
+// The witness, one scalar per wire, is uploaded before msm_a and freed after
+// msm_k; each wire MSM gathers its scalars from it.
+//
+// The peak of a stage is the resident SRS plus what the stage allocates: its
+// scalars, the witness for the wire MSMs, the points it streams in, and the
+// MSM buckets and bucket indices of icicle's bucket method. Sizes follow the
+// icicle device layout. MSM figures follow the allocations of msm.cu in
+// icicle v0.1.0, not measurements: the MSMs of a proof run through Commit,
+// whose bucket method uses a fixed window of CommitWindow bits.
+package planner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377fp "github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+	bls12377fr "github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	bls12381fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	bls12381fr "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	bn254fp "github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	bn254fr "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	bw6761fp "github.com/consensys/gnark-crypto/ecc/bw6-761/fp"
+	bw6761fr "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+)
+
+// ErrOverBudget is returned, wrapped, when no choice of resident buffers fits
+// the budget. The plan is still returned for reporting.
+var ErrOverBudget = errors.New("planner: proof does not fit the device memory budget")
+
+// Sizes are the device sizes of one curve.
+type Sizes struct {
+	ScalarBytes int64 // fr element
+	ScalarBits  int   // bits of the scalar field, for MSM windows
+	BaseBytes   int64 // fp element
+	G2Degree    int64 // degree over fp of the field holding G2 coordinates
+}
+
+// G1AffineBytes and the other methods give point sizes in the icicle layout.
+func (s Sizes) G1AffineBytes() int64     { return 2 * s.BaseBytes }
+func (s Sizes) G1ProjectiveBytes() int64 { return 3 * s.BaseBytes }
+func (s Sizes) G2AffineBytes() int64     { return 2 * s.G2Degree * s.BaseBytes }
+func (s Sizes) G2ProjectiveBytes() int64 { return 3 * s.G2Degree * s.BaseBytes }
+
+var sizes = map[ecc.ID]Sizes{
+	ecc.BN254:     {ScalarBytes: bn254fr.Bytes, ScalarBits: bn254fr.Bits, BaseBytes: bn254fp.Bytes, G2Degree: 2},
+	ecc.BLS12_377: {ScalarBytes: bls12377fr.Bytes, ScalarBits: bls12377fr.Bits, BaseBytes: bls12377fp.Bytes, G2Degree: 2},
+	ecc.BLS12_381: {ScalarBytes: bls12381fr.Bytes, ScalarBits: bls12381fr.Bits, BaseBytes: bls12381fp.Bytes, G2Degree: 2},
+	ecc.BW6_761:   {ScalarBytes: bw6761fr.Bytes, ScalarBits: bw6761fr.Bits, BaseBytes: bw6761fp.Bytes, G2Degree: 1},
+}
+
+// SizesOf returns the device sizes of curve.
+func SizesOf(curve ecc.ID) (Sizes, error) {
+	s, ok := sizes[curve]
+	if !ok {
+		return Sizes{}, fmt.Errorf("planner: unsupported curve %s", curve)
+	}
+
+	return s, nil
+}
+
+// KeySizes are the number of points in each proving key slice.
+type KeySizes struct {
+	G1A, G1B, G1K, G1Z int
+	G2B                int
+}
+
+// Input describes one proof.
+type Input struct {
+	Curve       ecc.ID
+	Constraints int
+	Wires       int
+	Key         KeySizes
+	// Budget is the device memory available to the proof, in bytes.
+	Budget int64
+}
+
+// Buffer is a proving key slice and where it lives.
+type Buffer struct {
+	Name     string
+	Bytes    int64
+	Resident bool
+}
+
+// Stage is the memory of one stage of the proof.
+type Stage struct {
+	Name string
+	// Working is what the stage allocates for itself, streamed points included.
+	Working int64
+	// Peak is Working plus the resident buffers.
+	Peak int64
+}
+
+// Plan is the outcome of Compute.
+type Plan struct {
+	Input    Input
+	Domain   int
+	Buffers  []Buffer
+	Stages   []Stage
+	Resident int64
+	Peak     int64
+}
+
+// Fits reports whether the plan peak is within the budget.
+func (p *Plan) Fits() bool { return p.Peak <= p.Input.Budget }
+
+// stage describes a stage before residency is decided.
+type stage struct {
+	name   string
+	local  int64  // bytes allocated by the stage itself
+	buffer string // proving key slice used by the stage, if any
+}
+
+// Compute builds the plan. It starts with every proving key slice resident
+// and streams the largest ones until the peak fits the budget. If even
+// streaming everything does not fit, the plan is returned with an error
+// wrapping ErrOverBudget.
+func Compute(in Input) (*Plan, error) {
+	s, err := SizesOf(in.Curve)
+	if err != nil {
+		return nil, err
+	}
+	if in.Constraints < 1 || in.Wires < 1 {
+		return nil, fmt.Errorf("planner: %d constraints and %d wires", in.Constraints, in.Wires)
+	}
+	if k := in.Key; k.G1A > in.Wires || k.G1B > in.Wires || k.G2B > in.Wires || k.G1K > in.Wires {
+		return nil, fmt.Errorf("planner: proving key slices of %d, %d, %d and %d points for %d wires", k.G1A, k.G1B, k.G2B, k.G1K, in.Wires)
+	}
+
+	n := Domain(in.Constraints)
+	witness := WitnessBytes(s, in.Wires)
+	buffers := []Buffer{
+		{Name: "pk.G1.A", Bytes: int64(in.Key.G1A) * s.G1AffineBytes(), Resident: true},
+		{Name: "pk.G1.B", Bytes: int64(in.Key.G1B) * s.G1AffineBytes(), Resident: true},
+		{Name: "pk.G2.B", Bytes: int64(in.Key.G2B) * s.G2AffineBytes(), Resident: true},
+		{Name: "pk.G1.K", Bytes: int64(in.Key.G1K) * s.G1AffineBytes(), Resident: true},
+		{Name: "pk.G1.Z", Bytes: int64(in.Key.G1Z) * s.G1AffineBytes(), Resident: true},
+	}
+	stages := []stage{
+		{name: "ntt", local: NttBytes(s, n)},
+		{name: "msm_a", local: witness + MsmG1Bytes(s, in.Key.G1A), buffer: "pk.G1.A"},
+		{name: "msm_b1", local: witness + MsmG1Bytes(s, in.Key.G1B), buffer: "pk.G1.B"},
+		{name: "msm_b2", local: witness + MsmG2Bytes(s, in.Key.G2B), buffer: "pk.G2.B"},
+		{name: "msm_k", local: witness + MsmG1Bytes(s, in.Key.G1K), buffer: "pk.G1.K"},
+		{name: "msm_z", local: MsmG1Bytes(s, in.Key.G1Z), buffer: "pk.G1.Z"},
+	}
+
+	// streaming order: largest buffer first
+	order := make([]int, len(buffers))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return buffers[order[i]].Bytes > buffers[order[j]].Bytes })
+
+	plan := evaluate(in, n, buffers, stages)
+	for _, i := range order {
+		if plan.Fits() {
+			break
+		}
+		buffers[i].Resident = false
+		plan = evaluate(in, n, buffers, stages)
+	}
+
+	if !plan.Fits() {
+		return plan, fmt.Errorf("%w: peak %s, budget %s", ErrOverBudget, formatBytes(plan.Peak), formatBytes(in.Budget))
+	}
+
+	return plan, nil
+}
+
+func evaluate(in Input, n int, buffers []Buffer, stages []stage) *Plan {
+	plan := &Plan{Input: in, Domain: n, Buffers: append([]Buffer{}, buffers...)}
+
+	streamed := make(map[string]int64)
+	for _, b := range buffers {
+		if b.Resident {
+			plan.Resident += b.Bytes
+		} else {
+			streamed[b.Name] = b.Bytes
+		}
+	}
+
+	for _, st := range stages {
+		working := st.local + streamed[st.buffer]
+		peak := plan.Resident + working
+		plan.Stages = append(plan.Stages, Stage{Name: st.name, Working: working, Peak: peak})
+		if peak > plan.Peak {
+			plan.Peak = peak
+		}
+	}
+
+	return plan
+}
+
+// Domain is the NTT size for constraints: the next power of two.
+func Domain(constraints int) int {
+	if constraints <= 1 {
+		return 1
+	}
+
+	return 1 << bits.Len(uint(constraints-1))
+}
+
+// NttBytes is the memory of computing H over a domain of size n: the a, b
+// and c vectors, the denominator, one NTT output buffer, forward and inverse
+// twiddles, and forward and inverse coset powers.
+func NttBytes(s Sizes, n int) int64 {
+	return 9 * int64(n) * s.ScalarBytes
+}
+
+// WitnessBytes is the memory of the witness of a circuit with wires wires.
+func WitnessBytes(s Sizes, wires int) int64 {
+	return int64(wires) * s.ScalarBytes
+}
+
+// CommitWindow is the bucket method window of icicle's Commit, which
+// MsmOnDevice runs: large_msm fixes it whatever the number of points.
+const CommitWindow = 16
+
+// BatchWindow is the bucket method window icicle's batched MSM picks for MSMs
+// of count points (get_optimal_c).
+func BatchWindow(count int) int {
+	if count < 17 {
+		return 1
+	}
+
+	return bits.Len(uint(count-1)) - 4
+}
+
+// MsmG1Bytes is the memory of a G1 MSM of count points through Commit whose
+// points are already on device: scalars, buckets, bucket and point indices,
+// and the result.
+func MsmG1Bytes(s Sizes, count int) int64 {
+	return msmBytes(s, count, s.G1ProjectiveBytes())
+}
+
+// MsmG2Bytes is the G2 counterpart of MsmG1Bytes.
+func MsmG2Bytes(s Sizes, count int) int64 {
+	return msmBytes(s, count, s.G2ProjectiveBytes())
+}
+
+// msmBytes follows bucket_method_msm with c = CommitWindow, unsigned digits
+// and the iterative bucket reduction. Its peak is either while the indices
+// are sorted, or during the first reduction pass, which halves the window
+// while the bucket metadata is still allocated. The large buckets, which
+// only skewed scalars need, are not counted.
+func msmBytes(s Sizes, count int, projectiveBytes int64) int64 {
+	if count == 0 {
+		return 0
+	}
+
+	n := int64(count)
+	windows := int64((s.ScalarBits + CommitWindow - 1) / CommitWindow)
+	buckets := windows << CommitWindow
+
+	scalars := n * s.ScalarBytes
+	indices := 2 * 4 * n * (windows + 1) // bucket_indices and point_indices, uint32
+	// the buckets, and the alternate keys and values of the radix sort
+	sorting := buckets*projectiveBytes + 2*4*n
+	// six uint32 arrays of at most one entry per bucket, and the buckets
+	// with two temporary halves and the target buckets of half the window
+	reducing := 6*4*buckets + (2*buckets+(2*windows)<<(CommitWindow/2))*projectiveBytes
+
+	return scalars + indices + max(sorting, reducing) + projectiveBytes
+}
+
+// MsmBatchG1Bytes is the memory of batch G1 MSMs of count points each
+// through icicle's batched MSM, with its own window BatchWindow(count):
+// scalars, buckets, bucket and point indices with their sorted copies,
+// bucket metadata, window sums and results.
+func MsmBatchG1Bytes(s Sizes, count, batch int) int64 {
+	if count == 0 || batch == 0 {
+		return 0
+	}
+
+	c := BatchWindow(count)
+	windows := int64((s.ScalarBits + c - 1) / c)
+	total := int64(batch) * int64(count)
+	buckets := int64(batch) * (windows << c)
+	p := s.G1ProjectiveBytes()
+
+	scalars := total * s.ScalarBytes
+	indices := 2*4*(total*windows+int64(count)) + 2*4*total*windows
+	// the radix sort temporaries are freed before the metadata is allocated
+	metadata := max(2*4*total*windows, 3*4*buckets)
+	points := buckets*p + windows*int64(batch)*p + int64(batch)*p
+
+	return scalars + indices + metadata + points
+}
+
+// Report writes the plan as tables for operators.
+func (p *Plan) Report(w io.Writer) error {
+	fmt.Fprintf(w, "curve %s, %d constraints (domain %d), %d wires\n", p.Input.Curve, p.Input.Constraints, p.Domain, p.Input.Wires)
+	fmt.Fprintf(w, "budget %s, peak %s, fits %t\n\n", formatBytes(p.Input.Budget), formatBytes(p.Peak), p.Fits())
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "buffer\tsize\tplacement\t\n")
+	for _, b := range p.Buffers {
+		placement := "streamed"
+		if b.Resident {
+			placement = "resident"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t\n", b.Name, formatBytes(b.Bytes), placement)
+	}
+	fmt.Fprintf(tw, "resident\t%s\t\t\n\n", formatBytes(p.Resident))
+
+	fmt.Fprintf(tw, "stage\tworking\tpeak\t\n")
+	for _, st := range p.Stages {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t\n", st.Name, formatBytes(st.Working), formatBytes(st.Peak))
+	}
+
+	return tw.Flush()
+}
+
+func formatBytes(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.2f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,180 @@
+package planner
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomain(t *testing.T) {
+	for constraints, n := range map[int]int{1: 1, 2: 2, 3: 4, 1 << 20: 1 << 20, 1<<20 + 1: 1 << 21} {
+		assert.Equal(t, n, Domain(constraints), constraints)
+	}
+}
+
+func TestBatchWindow(t *testing.T) {
+	for count, c := range map[int]int{1: 1, 16: 1, 17: 1, 32: 1, 33: 2, 1 << 20: 16, 1<<20 + 1: 17} {
+		assert.Equal(t, c, BatchWindow(count), count)
+	}
+}
+
+func TestSizes(t *testing.T) {
+	s, err := SizesOf(ecc.BN254)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(64), s.G1AffineBytes())
+	assert.Equal(t, int64(96), s.G1ProjectiveBytes())
+	assert.Equal(t, int64(128), s.G2AffineBytes())
+	assert.Equal(t, int64(192), s.G2ProjectiveBytes())
+
+	s, err = SizesOf(ecc.BW6_761)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(48), s.ScalarBytes)
+	assert.Equal(t, int64(192), s.G1AffineBytes())
+	assert.Equal(t, int64(192), s.G2AffineBytes())
+
+	_, err = SizesOf(ecc.SECP256K1)
+	assert.Error(t, err)
+}
+
+func TestStageBytes(t *testing.T) {
+	s, _ := SizesOf(ecc.BN254)
+
+	// 9 vectors of 2^20 32-byte scalars
+	assert.Equal(t, int64(301989888), NttBytes(s, 1<<20))
+
+	assert.Zero(t, MsmG1Bytes(s, 0))
+
+	// 2^20 scalars of 32 bytes, 16 windows of 2^16 buckets and 17 slices of
+	// uint32 bucket and point indices; the first reduction pass, with 2^20
+	// buckets, their two halves, 2^13 target buckets and the six metadata
+	// arrays, outweighs the sort; plus the result
+	assert.Equal(t, int64(33554432+142606336+25165824+(2*1048576+8192)*96+96), MsmG1Bytes(s, 1<<20))
+
+	// 2^10 MSMs of 2^10 points: c = 6, 43 windows of 64 buckets each
+	assert.Equal(t, int64(131072+2826240+1409024+1073664), MsmBatchG1Bytes(s, 1<<10, 4))
+	assert.Zero(t, MsmBatchG1Bytes(s, 1<<10, 0))
+
+	assert.Equal(t, int64(33554432), WitnessBytes(s, 1<<20))
+}
+
+// TestMsmBytes pins the commit path model to the allocations of large_msm
+// in icicle v0.1.0's msm.cu: c = 16 whatever the count.
+func TestMsmBytes(t *testing.T) {
+	for _, test := range []struct {
+		curve  ecc.ID
+		count  int
+		g1, g2 int64
+	}{
+		{ecc.BN254, 1, 227279112, 429392232},
+		{ecc.BN254, 1 << 10, 227450976, 429564096},
+		{ecc.BN254, 1 << 20, 403439712, 605552832},
+		{ecc.BN254, 1 << 24, 3053453408, 3247964352},
+		{ecc.BW6_761, 1, 947257880, 947257880},
+		{ecc.BW6_761, 1 << 20, 1207304480, 1207304480},
+		{ecc.BW6_761, 1 << 24, 5108007200, 5108007200},
+	} {
+		s, err := SizesOf(test.curve)
+		assert.NoError(t, err)
+		assert.Equal(t, test.g1, MsmG1Bytes(s, test.count), "%s %d", test.curve, test.count)
+		assert.Equal(t, test.g2, MsmG2Bytes(s, test.count), "%s %d", test.curve, test.count)
+	}
+}
+
+func testInput(budget int64) Input {
+	return Input{
+		Curve:       ecc.BN254,
+		Constraints: 1 << 20,
+		Wires:       1 << 20,
+		Key:         KeySizes{G1A: 1 << 20, G1B: 1 << 19, G1K: 1<<20 - 10, G1Z: 1<<20 - 1, G2B: 1 << 19},
+		Budget:      budget,
+	}
+}
+
+func TestComputeAllResident(t *testing.T) {
+	s, _ := SizesOf(ecc.BN254)
+	plan, err := Compute(testInput(1 << 40))
+	assert.NoError(t, err)
+	assert.True(t, plan.Fits())
+	assert.Equal(t, 1<<20, plan.Domain)
+
+	var resident int64
+	for _, b := range plan.Buffers {
+		assert.True(t, b.Resident, b.Name)
+		resident += b.Bytes
+	}
+	assert.Equal(t, resident, plan.Resident)
+	assert.Equal(t, int64(1<<20+1<<19+1<<20-10+1<<20-1)*64+int64(1<<19)*128, resident)
+
+	assert.Equal(t, "ntt", plan.Stages[0].Name)
+	assert.Equal(t, NttBytes(s, 1<<20), plan.Stages[0].Working)
+
+	// the wire MSMs hold the witness; msm_b2, over G2, is the largest
+	// working set
+	assert.Equal(t, "msm_a", plan.Stages[1].Name)
+	assert.Equal(t, WitnessBytes(s, 1<<20)+MsmG1Bytes(s, 1<<20), plan.Stages[1].Working)
+	assert.Equal(t, "msm_b2", plan.Stages[3].Name)
+	assert.Equal(t, WitnessBytes(s, 1<<20)+MsmG2Bytes(s, 1<<19), plan.Stages[3].Working)
+	assert.Equal(t, MsmG1Bytes(s, 1<<20-1), plan.Stages[5].Working)
+	assert.Equal(t, resident+plan.Stages[3].Working, plan.Peak)
+}
+
+func TestComputeStreamsLargestFirst(t *testing.T) {
+	all, err := Compute(testInput(1 << 40))
+	assert.NoError(t, err)
+
+	// just under the all-resident peak, streaming pk.G1.A, the first of the
+	// largest slices, is enough: msm_a stays under msm_b2
+	plan, err := Compute(testInput(all.Peak - 1))
+	assert.NoError(t, err)
+	assert.True(t, plan.Fits())
+
+	for _, b := range plan.Buffers {
+		assert.Equal(t, b.Name != "pk.G1.A", b.Resident, b.Name)
+	}
+	assert.Equal(t, all.Resident-all.Buffers[0].Bytes, plan.Resident)
+
+	// the streamed slice now counts in its stage only
+	assert.Equal(t, all.Stages[1].Working+all.Buffers[0].Bytes, plan.Stages[1].Working)
+	assert.Equal(t, all.Stages[2].Working, plan.Stages[2].Working)
+}
+
+func TestComputeOverBudget(t *testing.T) {
+	plan, err := Compute(testInput(1 << 20))
+	assert.ErrorIs(t, err, ErrOverBudget)
+	assert.NotNil(t, plan)
+	assert.False(t, plan.Fits())
+	assert.Zero(t, plan.Resident)
+	for _, b := range plan.Buffers {
+		assert.False(t, b.Resident, b.Name)
+	}
+
+	_, err = Compute(Input{Curve: ecc.BN254})
+	assert.Error(t, err)
+	_, err = Compute(Input{Curve: ecc.SECP256K1, Constraints: 1, Wires: 1})
+	assert.Error(t, err)
+
+	// a key slice cannot have more points than there are wires
+	in := testInput(1 << 40)
+	in.Wires = 1 << 19
+	_, err = Compute(in)
+	assert.Error(t, err)
+}
+
+func TestReport(t *testing.T) {
+	plan, err := Compute(testInput(640 << 20))
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, plan.Report(&buf))
+	for _, s := range []string{"curve bn254", "domain 1048576", "budget 640.00 MiB", "pk.G2.B", "streamed", "resident", "msm_z"} {
+		assert.Contains(t, buf.String(), s)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.50 KiB", formatBytes(1536))
+	assert.Equal(t, "2.00 GiB", formatBytes(2<<30))
+}
@@ -0,0 +1,87 @@
+package iciclegnark
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark/internal/hostcurve"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var stateCurve = hostcurve.NewInts(ecc.BLS24_317)
+
+// TestConcurrentState replays what every call of a curve package does with
+// the shared state (resolve the logger, observe, sample the verification
+// mode, load the device hooks and the point validation, look the curve up)
+// from many goroutines while others replace that state; run with -race.
+func TestConcurrentState(t *testing.T) {
+	defer SetObserver(nil)
+	defer SetLogger(nil)
+	defer SetVerification(Verification{})
+	defer SetDeviceHooks(nil)
+	defer SetPointValidation(true)
+
+	if _, err := Get[int, int, int, int, int](ecc.BLS24_317); err != nil {
+		Register(ecc.BLS24_317, stateCurve)
+	}
+
+	m := NewMetrics()
+	SetObserver(m)
+	debug := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	const calls, iterations = 8, 50
+	var wg sync.WaitGroup
+	for g := 0; g < calls; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				log := ApplyOptions().Logger
+				done := Observe(Operation{Name: "MsmOnDevice", Curve: ecc.BLS24_317, Size: 3})
+				CurrentVerification().Sample()
+				CurrentDeviceHooks()
+				PointValidation()
+
+				c, err := Get[int, int, int, int, int](ecc.BLS24_317)
+				require.NoError(t, err)
+				res, err := Commit(c, []int{1, 2, 3}, []int{4, 5, 6})
+				assert.Equal(t, 32, res)
+				log.Debug("msm", slog.Int("size", 3))
+				done(err)
+			}
+		}()
+	}
+
+	// the state is replaced under the calls, always keeping m installed
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < iterations; i++ {
+			SetObserver(Observers{m, NewSlogObserver(debug)})
+			SetLogger(debug)
+			SetVerification(Verification{Enabled: true, SampleRate: 0.5})
+			SetDeviceHooks(nopHooks{})
+			SetPointValidation(false)
+			Curves()
+			SetObserver(m)
+			SetLogger(nil)
+			SetVerification(Verification{})
+			SetDeviceHooks(nil)
+			SetPointValidation(true)
+			m.WriteTo(io.Discard)
+		}
+	}()
+	wg.Wait()
+
+	var buf bytes.Buffer
+	_, err := m.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), `iciclegnark_operations_total{op="MsmOnDevice",curve="bls24_317",status="ok"} 400`+"\n")
+	assert.Contains(t, buf.String(), "iciclegnark_operations_in_flight 0\n")
+	assert.Zero(t, stateCurve.Live())
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"unsafe"
+
+	"github.com/ingonyama-zk/iciclegnark"
+	"github.com/ingonyama-zk/iciclegnark/planner"
+	"github.com/ingonyama-zk/iciclegnark/stream"
+)
+
+// MsmJob returns a job computing into res the G1 MSM of scalars with the
+// points at points_d, typically a resident proving key. Its memory is the
+// planner estimate.
+func MsmJob[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], scalars []Fr, points_d unsafe.Pointer, res *G1Jac) (Job, error) {
+	sizes, err := planner.SizesOf(c.ID())
+	if err != nil {
+		return Job{}, err
+	}
+
+	return Job{
+		Kind:   Msm,
+		Memory: planner.MsmG1Bytes(sizes, len(scalars)),
+		Run: func(ctx context.Context, s *stream.Stream) error {
+			scalars_d := stream.CopyScalarsToDevice(s, c, scalars)
+			msm := stream.MsmOnDevice(s, c, scalars_d, stream.Ready(points_d), len(scalars))
+			stream.FreeDevicePointer(s, c, scalars_d)
+
+			r, err := msm.Wait(ctx)
+			if err != nil {
+				return err
+			}
+			*res = r
+
+			return nil
+		},
+	}, nil
+}
+
+// NttJob returns a job replacing values with their forward NTT, natural order
+// in and out, with the twiddles at twiddles_d. Its memory is the input and
+// output buffers.
+func NttJob[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], values []Fr, twiddles_d unsafe.Pointer) (Job, error) {
+	sizes, err := planner.SizesOf(c.ID())
+	if err != nil {
+		return Job{}, err
+	}
+	size := len(values)
+
+	return Job{
+		Kind:   Ntt,
+		Memory: 2 * int64(size) * sizes.ScalarBytes,
+		Run: func(ctx context.Context, s *stream.Stream) error {
+			values_d := stream.CopyScalarsToDevice(s, c, values)
+			out_d := stream.CopyScalarsToDevice(s, c, make([]Fr, size))
+			ntt := stream.Then(s, out_d, func(out unsafe.Pointer) (unsafe.Pointer, error) {
+				// values_d was enqueued before out_d and has completed
+				in, err := values_d.Wait(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return out, c.NttOnDevice(out, in, twiddles_d, nil, size, size, false)
+			})
+			res := stream.CopyScalarsFromDevice(s, c, ntt, size)
+			stream.FreeDevicePointer(s, c, values_d)
+			stream.FreeDevicePointer(s, c, out_d)
+
+			r, err := res.Wait(ctx)
+			if err != nil {
+				return err
+			}
+			copy(values, r)
+
+			return nil
+		},
+	}, nil
+}
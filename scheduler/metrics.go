@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Stats is a snapshot of the queue.
+type Stats struct {
+	Queued  int
+	Running int
+	// Budget and InUse are device memory, in bytes.
+	Budget int64
+	InUse  int64
+
+	Submitted uint64
+	Rejected  uint64 // by Submit: invalid, too large or queue full
+	Cancelled uint64 // left the queue before running
+	// QueueWait is the total time the started jobs waited in the queue.
+	QueueWait time.Duration
+	Kinds     map[string]KindStats
+}
+
+// KindStats counts the jobs of one kind that ran.
+type KindStats struct {
+	Completed uint64
+	Failed    uint64
+	// Run is their total run time, stream drained.
+	Run time.Duration
+}
+
+// Stats returns a snapshot of the queue and counters.
+func (s *Scheduler) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	stats.Queued = len(s.queue)
+	stats.Running = s.config.Streams - len(s.streams)
+	stats.InUse = s.inUse
+	stats.Kinds = make(map[string]KindStats, len(s.stats.Kinds))
+	for kind, k := range s.stats.Kinds {
+		stats.Kinds[kind] = k
+	}
+
+	return stats
+}
+
+// WriteTo writes Stats in the Prometheus text format.
+func (s *Scheduler) WriteTo(w io.Writer) (int64, error) {
+	stats := s.Stats()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	gauge := func(name, help string, v float64) {
+		fmt.Fprintf(cw, "# HELP %s %s\n# TYPE %s gauge\n%s %g\n", name, help, name, name, v)
+	}
+	counter := func(name, help string, v float64) {
+		fmt.Fprintf(cw, "# HELP %s %s\n# TYPE %s counter\n%s %g\n", name, help, name, name, v)
+	}
+
+	gauge("iciclegnark_scheduler_queued_jobs", "Jobs waiting in the queue.", float64(stats.Queued))
+	gauge("iciclegnark_scheduler_running_jobs", "Jobs running.", float64(stats.Running))
+	gauge("iciclegnark_scheduler_budget_bytes", "Device memory budget.", float64(stats.Budget))
+	gauge("iciclegnark_scheduler_in_use_bytes", "Device memory declared by the running jobs.", float64(stats.InUse))
+	counter("iciclegnark_scheduler_submitted_total", "Jobs submitted.", float64(stats.Submitted))
+	counter("iciclegnark_scheduler_rejected_total", "Jobs rejected by Submit.", float64(stats.Rejected))
+	counter("iciclegnark_scheduler_cancelled_total", "Jobs that left the queue before running.", float64(stats.Cancelled))
+	counter("iciclegnark_scheduler_queue_wait_seconds_total", "Time started jobs waited in the queue.", stats.QueueWait.Seconds())
+
+	kinds := make([]string, 0, len(stats.Kinds))
+	for kind := range stats.Kinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	fmt.Fprintln(cw, "# HELP iciclegnark_scheduler_jobs_total Jobs that ran, by kind and status.")
+	fmt.Fprintln(cw, "# TYPE iciclegnark_scheduler_jobs_total counter")
+	for _, kind := range kinds {
+		k := stats.Kinds[kind]
+		fmt.Fprintf(cw, "iciclegnark_scheduler_jobs_total{kind=%q,status=\"ok\"} %d\n", kind, k.Completed)
+		fmt.Fprintf(cw, "iciclegnark_scheduler_jobs_total{kind=%q,status=\"error\"} %d\n", kind, k.Failed)
+	}
+	fmt.Fprintln(cw, "# HELP iciclegnark_scheduler_run_seconds_total Run time of the jobs, by kind.")
+	fmt.Fprintln(cw, "# TYPE iciclegnark_scheduler_run_seconds_total counter")
+	for _, kind := range kinds {
+		fmt.Fprintf(cw, "iciclegnark_scheduler_run_seconds_total{kind=%q} %g\n", kind, stats.Kinds[kind].Run.Seconds())
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+
+	return cw.n, cw.w.Flush()
+}
+
+// ServeHTTP serves the metrics for scraping.
+func (s *Scheduler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	s.WriteTo(w)
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+
+	return n, err
+}
@@ -0,0 +1,295 @@
+// Package scheduler admits concurrent device jobs, such as proofs, MSMs and
+// NTTs, within a device memory budget.
+//
+// Jobs wait in a queue ordered by priority, then submission. The job at the
+// head runs as soon as its memory fits in what the running jobs leave of the
+// budget and a stream is free; jobs behind it wait, so a large job is never
+// starved by smaller ones. Each running job gets its own stream.Stream: with
+// one stream jobs are serialized, with more their kernels interleave.
+//
+// Memory is what the job declares, typically estimated with package planner;
+// the scheduler does not measure allocations.
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/ingonyama-zk/iciclegnark/stream"
+)
+
+// Kinds of jobs, used to label metrics.
+const (
+	Prove = "prove"
+	Msm   = "msm"
+	Ntt   = "ntt"
+)
+
+var (
+	// ErrTooLarge is returned by Submit for jobs needing more than the budget.
+	ErrTooLarge = errors.New("scheduler: job does not fit the device memory budget")
+	// ErrQueueFull is returned by Submit when the queue holds QueueLimit jobs.
+	ErrQueueFull = errors.New("scheduler: queue is full")
+	// ErrClosed is returned by Submit after Close, and by the jobs still
+	// queued when it is called.
+	ErrClosed = errors.New("scheduler: closed")
+)
+
+// Job is one unit of device work.
+type Job struct {
+	Kind     string
+	Priority int // higher runs first
+	// Memory is the device memory the job holds at most, in bytes.
+	Memory int64
+	// Run enqueues the work of the job on s. The scheduler waits for s to
+	// drain before releasing the memory of the job. ctx is the context of
+	// Submit. A panic in Run fails the job with a *stream.PanicError.
+	Run func(ctx context.Context, s *stream.Stream) error
+}
+
+// Config sets the limits of a Scheduler.
+type Config struct {
+	// Budget is the device memory shared by the running jobs, in bytes.
+	Budget int64
+	// Streams is the number of jobs running at once, 1 if zero.
+	Streams int
+	// QueueLimit is the number of waiting jobs, unlimited if zero.
+	QueueLimit int
+}
+
+// Ticket tracks a submitted job. It is a stream.Event, so streams can wait
+// on it.
+type Ticket struct {
+	job      Job
+	ctx      context.Context
+	seq      uint64
+	index    int // in the queue, -1 once out of it
+	queued   time.Time
+	done     chan struct{}
+	err      error
+	stopWait func() bool
+}
+
+// Done is closed when the job has finished or left the queue.
+func (t *Ticket) Done() <-chan struct{} { return t.done }
+
+// Err returns the error of the job, nil until it is done.
+func (t *Ticket) Err() error {
+	select {
+	case <-t.done:
+		return t.err
+	default:
+		return nil
+	}
+}
+
+// Wait blocks until the job is done or ctx is done. Cancelling ctx does not
+// cancel the job; cancel the context given to Submit for that.
+func (t *Ticket) Wait(ctx context.Context) error {
+	select {
+	case <-t.done:
+		return t.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type queue []*Ticket
+
+func (q queue) Len() int { return len(q) }
+
+func (q queue) Less(i, j int) bool {
+	if q[i].job.Priority != q[j].job.Priority {
+		return q[i].job.Priority > q[j].job.Priority
+	}
+
+	return q[i].seq < q[j].seq
+}
+
+func (q queue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index, q[j].index = i, j
+}
+
+func (q *queue) Push(x any) {
+	t := x.(*Ticket)
+	t.index = len(*q)
+	*q = append(*q, t)
+}
+
+func (q *queue) Pop() any {
+	old := *q
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	t.index = -1
+	*q = old[:len(old)-1]
+
+	return t
+}
+
+// Scheduler runs jobs within its Config.
+type Scheduler struct {
+	config Config
+
+	mu      sync.Mutex
+	queue   queue
+	streams []*stream.Stream // free streams
+	inUse   int64
+	seq     uint64
+	closed  bool
+	stats   Stats
+}
+
+// New returns a scheduler with cfg.
+func New(cfg Config) (*Scheduler, error) {
+	if cfg.Budget <= 0 {
+		return nil, fmt.Errorf("scheduler: budget of %d bytes", cfg.Budget)
+	}
+	if cfg.Streams == 0 {
+		cfg.Streams = 1
+	}
+	if cfg.Streams < 0 || cfg.QueueLimit < 0 {
+		return nil, fmt.Errorf("scheduler: %d streams and a queue of %d", cfg.Streams, cfg.QueueLimit)
+	}
+
+	s := &Scheduler{config: cfg, stats: Stats{Budget: cfg.Budget, Kinds: make(map[string]KindStats)}}
+	for i := 0; i < cfg.Streams; i++ {
+		s.streams = append(s.streams, stream.New())
+	}
+
+	return s, nil
+}
+
+// Submit queues job. Cancelling ctx removes the job from the queue, or is
+// seen by its Run if it has started.
+func (s *Scheduler) Submit(ctx context.Context, job Job) (*Ticket, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.closed:
+		return nil, ErrClosed
+	case job.Memory < 0:
+		s.stats.Rejected++
+		return nil, fmt.Errorf("scheduler: %s job of %d bytes", job.Kind, job.Memory)
+	case job.Memory > s.config.Budget:
+		s.stats.Rejected++
+		return nil, fmt.Errorf("%w: %s job of %d bytes for a budget of %d", ErrTooLarge, job.Kind, job.Memory, s.config.Budget)
+	case s.config.QueueLimit > 0 && len(s.queue) >= s.config.QueueLimit:
+		s.stats.Rejected++
+		return nil, ErrQueueFull
+	}
+
+	s.seq++
+	t := &Ticket{job: job, ctx: ctx, seq: s.seq, queued: time.Now(), done: make(chan struct{})}
+	heap.Push(&s.queue, t)
+	s.stats.Submitted++
+	t.stopWait = context.AfterFunc(ctx, func() { s.cancel(t) })
+
+	s.dispatch()
+
+	return t, nil
+}
+
+// Do submits job and waits for it.
+func (s *Scheduler) Do(ctx context.Context, job Job) error {
+	t, err := s.Submit(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	return t.Wait(ctx)
+}
+
+// Close fails the queued jobs with ErrClosed and rejects new ones. Running
+// jobs finish.
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for len(s.queue) > 0 {
+		t := heap.Pop(&s.queue).(*Ticket)
+		t.stopWait()
+		s.stats.Cancelled++
+		s.finish(t, ErrClosed)
+	}
+}
+
+// cancel removes t from the queue if it is still there.
+func (s *Scheduler) cancel(t *Ticket) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.index < 0 {
+		return
+	}
+	heap.Remove(&s.queue, t.index)
+	s.stats.Cancelled++
+	s.finish(t, t.ctx.Err())
+
+	// the head may have changed
+	s.dispatch()
+}
+
+// dispatch starts queued jobs while the head fits. s.mu is held.
+func (s *Scheduler) dispatch() {
+	for len(s.queue) > 0 && len(s.streams) > 0 && s.queue[0].job.Memory <= s.config.Budget-s.inUse {
+		t := heap.Pop(&s.queue).(*Ticket)
+		t.stopWait()
+
+		st := s.streams[len(s.streams)-1]
+		s.streams = s.streams[:len(s.streams)-1]
+		s.inUse += t.job.Memory
+
+		s.stats.QueueWait += time.Since(t.queued)
+		go s.run(t, st)
+	}
+}
+
+func (s *Scheduler) run(t *Ticket, st *stream.Stream) {
+	start := time.Now()
+	err := runJob(t, st)
+	// the memory of the job is held until its operations have completed
+	st.Synchronize(context.Background())
+	elapsed := time.Since(start)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.streams = append(s.streams, st)
+	s.inUse -= t.job.Memory
+
+	k := s.stats.Kinds[t.job.Kind]
+	k.Run += elapsed
+	if err != nil {
+		k.Failed++
+	} else {
+		k.Completed++
+	}
+	s.stats.Kinds[t.job.Kind] = k
+
+	s.finish(t, err)
+	s.dispatch()
+}
+
+// runJob calls the Run of t and turns a panic into a *stream.PanicError.
+func runJob(t *Ticket, st *stream.Stream) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &stream.PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return t.job.Run(t.ctx, st)
+}
+
+func (s *Scheduler) finish(t *Ticket, err error) {
+	t.err = err
+	close(t.done)
+}
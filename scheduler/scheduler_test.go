@@ -0,0 +1,235 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark/faults"
+	"github.com/ingonyama-zk/iciclegnark/internal/hostcurve"
+	"github.com/ingonyama-zk/iciclegnark/stream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blocking returns a job of memory that records its name in order once it
+// starts and runs until release is closed.
+func blocking(name string, priority int, memory int64, order *[]string, mu *sync.Mutex, release chan struct{}) Job {
+	return Job{
+		Kind:     Msm,
+		Priority: priority,
+		Memory:   memory,
+		Run: func(ctx context.Context, s *stream.Stream) error {
+			mu.Lock()
+			*order = append(*order, name)
+			mu.Unlock()
+			select {
+			case <-release:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+}
+
+func submit(t *testing.T, s *Scheduler, job Job) *Ticket {
+	ticket, err := s.Submit(context.Background(), job)
+	require.NoError(t, err)
+
+	return ticket
+}
+
+func TestPriority(t *testing.T) {
+	s, err := New(Config{Budget: 100})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var order []string
+	release := make(chan struct{})
+	first := submit(t, s, blocking("first", 0, 10, &order, &mu, release))
+
+	var tickets []*Ticket
+	for _, j := range []struct {
+		name     string
+		priority int
+	}{{"low", 0}, {"high", 2}, {"mid", 1}, {"low2", 0}} {
+		tickets = append(tickets, submit(t, s, blocking(j.name, j.priority, 10, &order, &mu, release)))
+	}
+	assert.Equal(t, 4, s.Stats().Queued)
+
+	close(release)
+	for _, ticket := range append(tickets, first) {
+		assert.NoError(t, ticket.Wait(context.Background()))
+	}
+	assert.Equal(t, []string{"first", "high", "mid", "low", "low2"}, order)
+}
+
+func TestBudget(t *testing.T) {
+	s, err := New(Config{Budget: 100, Streams: 4})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var order []string
+	releaseA, release := make(chan struct{}), make(chan struct{})
+	a := submit(t, s, blocking("a", 0, 60, &order, &mu, releaseA))
+	b := submit(t, s, blocking("b", 0, 60, &order, &mu, release))
+	// c fits but waits behind b
+	c := submit(t, s, blocking("c", 0, 10, &order, &mu, release))
+
+	stats := s.Stats()
+	assert.Equal(t, 1, stats.Running)
+	assert.Equal(t, 2, stats.Queued)
+	assert.Equal(t, int64(60), stats.InUse)
+
+	close(releaseA)
+	require.NoError(t, a.Wait(context.Background()))
+	close(release)
+	require.NoError(t, b.Wait(context.Background()))
+	require.NoError(t, c.Wait(context.Background()))
+
+	stats = s.Stats()
+	assert.Zero(t, stats.Running)
+	assert.Zero(t, stats.InUse)
+	assert.Equal(t, uint64(3), stats.Kinds[Msm].Completed)
+}
+
+func TestCancel(t *testing.T) {
+	s, err := New(Config{Budget: 100})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var order []string
+	release := make(chan struct{})
+	runningCtx, cancelRunning := context.WithCancel(context.Background())
+	running, err := s.Submit(runningCtx, blocking("running", 0, 10, &order, &mu, release))
+	require.NoError(t, err)
+
+	queuedCtx, cancelQueued := context.WithCancel(context.Background())
+	queued, err := s.Submit(queuedCtx, blocking("queued", 0, 10, &order, &mu, release))
+	require.NoError(t, err)
+	next := submit(t, s, blocking("next", 0, 10, &order, &mu, release))
+
+	// the queued job leaves the queue without running
+	cancelQueued()
+	assert.ErrorIs(t, queued.Wait(context.Background()), context.Canceled)
+	assert.Equal(t, 1, s.Stats().Queued)
+
+	// the running job sees its context
+	cancelRunning()
+	assert.ErrorIs(t, running.Wait(context.Background()), context.Canceled)
+
+	close(release)
+	require.NoError(t, next.Wait(context.Background()))
+	assert.Equal(t, []string{"running", "next"}, order)
+
+	stats := s.Stats()
+	assert.Equal(t, uint64(1), stats.Cancelled)
+	assert.Equal(t, uint64(1), stats.Kinds[Msm].Failed)
+	assert.Equal(t, uint64(1), stats.Kinds[Msm].Completed)
+}
+
+func TestReject(t *testing.T) {
+	_, err := New(Config{})
+	assert.Error(t, err)
+
+	s, err := New(Config{Budget: 100, QueueLimit: 1})
+	require.NoError(t, err)
+
+	_, err = s.Submit(context.Background(), Job{Memory: 101})
+	assert.ErrorIs(t, err, ErrTooLarge)
+	_, err = s.Submit(context.Background(), Job{Memory: -1})
+	assert.Error(t, err)
+
+	var mu sync.Mutex
+	var order []string
+	release := make(chan struct{})
+	running := submit(t, s, blocking("running", 0, 10, &order, &mu, release))
+	queued := submit(t, s, blocking("queued", 0, 10, &order, &mu, release))
+	_, err = s.Submit(context.Background(), blocking("full", 0, 10, &order, &mu, release))
+	assert.ErrorIs(t, err, ErrQueueFull)
+	assert.Equal(t, uint64(3), s.Stats().Rejected)
+
+	// closing fails the queued jobs and lets the running one finish
+	s.Close()
+	assert.ErrorIs(t, queued.Wait(context.Background()), ErrClosed)
+	_, err = s.Submit(context.Background(), Job{})
+	assert.ErrorIs(t, err, ErrClosed)
+	close(release)
+	assert.NoError(t, running.Wait(context.Background()))
+}
+
+func TestPanic(t *testing.T) {
+	s, err := New(Config{Budget: 100})
+	require.NoError(t, err)
+
+	panicked := submit(t, s, Job{Kind: Msm, Memory: 100, Run: func(context.Context, *stream.Stream) error {
+		panic("boom")
+	}})
+	assert.ErrorIs(t, panicked.Wait(context.Background()), stream.ErrPanic)
+
+	// the stream and the memory of the job are released
+	assert.NoError(t, s.Do(context.Background(), Job{Kind: Msm, Memory: 100, Run: func(context.Context, *stream.Stream) error {
+		return nil
+	}}))
+	stats := s.Stats()
+	assert.Zero(t, stats.InUse)
+	assert.Equal(t, uint64(1), stats.Kinds[Msm].Failed)
+	assert.Equal(t, uint64(1), stats.Kinds[Msm].Completed)
+}
+
+func TestWriteTo(t *testing.T) {
+	s, err := New(Config{Budget: 100})
+	require.NoError(t, err)
+
+	require.NoError(t, s.Do(context.Background(), Job{Kind: Ntt, Memory: 1, Run: func(context.Context, *stream.Stream) error { return nil }}))
+	require.Error(t, s.Do(context.Background(), Job{Kind: Ntt, Memory: 1, Run: func(context.Context, *stream.Stream) error { return errors.New("failed") }}))
+
+	var buf bytes.Buffer
+	_, err = s.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "iciclegnark_scheduler_budget_bytes 100\n")
+	assert.Contains(t, buf.String(), "iciclegnark_scheduler_submitted_total 2\n")
+	assert.Contains(t, buf.String(), "iciclegnark_scheduler_jobs_total{kind=\"ntt\",status=\"ok\"} 1\n")
+	assert.Contains(t, buf.String(), "iciclegnark_scheduler_jobs_total{kind=\"ntt\",status=\"error\"} 1\n")
+}
+
+func TestJobs(t *testing.T) {
+	c := faults.Wrap[int, int, int, int, int](hostcurve.NewInts(ecc.BN254))
+	s, err := New(Config{Budget: 1 << 30, Streams: 2})
+	require.NoError(t, err)
+
+	points_d, err := c.CopyG1PointsToDevice([]int{1, 2, 3, 4})
+	require.NoError(t, err)
+
+	results := make([]int, 16)
+	tickets := make([]*Ticket, len(results))
+	for i := range results {
+		job, err := MsmJob[int, int, int, int, int](c, []int{i, i, i, i}, points_d, &results[i])
+		require.NoError(t, err)
+		tickets[i] = submit(t, s, job)
+	}
+	for i, ticket := range tickets {
+		require.NoError(t, ticket.Wait(context.Background()))
+		assert.Equal(t, 10*i, results[i])
+	}
+
+	values := []int{1, 2, 3, 4}
+	job, err := NttJob[int, int, int, int, int](c, values, nil)
+	require.NoError(t, err)
+	require.NoError(t, s.Do(context.Background(), job))
+	assert.Equal(t, []int{1, 3, 6, 10}, values)
+
+	// a failed upload fails the job and frees what it allocated
+	job, err = NttJob[int, int, int, int, int](c, values, nil)
+	require.NoError(t, err)
+	c.Inject(faults.FailAllocation(2))
+	assert.ErrorIs(t, s.Do(context.Background(), job), faults.ErrInjected)
+
+	c.FreeDevicePointer(points_d)
+	assert.Zero(t, c.Live())
+	assert.Zero(t, c.BadFrees())
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+)
+
+func init() {
+	register(newCurve[fr.Element, bls12381.G1Affine, bls12381.G1Jac](ecc.BLS12_381,
+		func(values []fr.Element, inverse, coset bool) {
+			domain := fft.NewDomain(uint64(len(values)))
+			var opts []fft.Option
+			if coset {
+				opts = append(opts, fft.OnCoset())
+			}
+			if inverse {
+				domain.FFTInverse(values, fft.DIF, opts...)
+			} else {
+				domain.FFT(values, fft.DIF, opts...)
+			}
+			fft.BitReverse(values)
+		},
+		func(n int, inverse bool) []fr.Element {
+			domain := fft.NewDomain(uint64(n))
+			g := domain.FrMultiplicativeGen
+			if inverse {
+				g = domain.FrMultiplicativeGenInv
+			}
+			powers := make([]fr.Element, n)
+			powers[0].SetOne()
+			for i := 1; i < n; i++ {
+				powers[i].Mul(&powers[i-1], &g)
+			}
+			return powers
+		},
+	))
+}
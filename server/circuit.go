@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Circuit proves the statements of one circuit on the engine of its curve.
+// Groth16 serves gnark's Groth16 prover; other provers implement it to be
+// served by Prove.
+type Circuit interface {
+	Curve() ecc.ID
+	// Load is called once, by Server.Register, to prepare the circuit on e,
+	// such as making its proving key resident there.
+	Load(e Engine) error
+	Prove(ctx context.Context, e Engine, witness []byte) ([]byte, error)
+}
+
+// Commitment is the Circuit of KZG commitments with the proving key of an
+// SRS: the witness is the coefficients of a polynomial, encoded as scalars,
+// and the proof its commitment, an encoded point.
+//
+// It is not a proof system: the "proof" is the commitment alone, one MSM on
+// the engine, with no opening proof.
+type Commitment struct {
+	ID ecc.ID
+	// Key is the name of the SRS G1 points on the engine.
+	Key string
+	// Points are loaded under Key by Load. If nil, the key must have been
+	// loaded on the engine beforehand.
+	Points []byte
+}
+
+func (c *Commitment) Curve() ecc.ID { return c.ID }
+
+func (c *Commitment) Load(e Engine) error {
+	if c.Points == nil {
+		return nil
+	}
+
+	return e.LoadKey(c.Key, c.Points)
+}
+
+func (c *Commitment) Prove(ctx context.Context, e Engine, witness []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return e.MsmKey(c.Key, witness)
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Client calls a Server.
+type Client struct {
+	url  string
+	http *http.Client
+}
+
+// NewClient returns a client of the server at url, e.g. http://localhost:8547,
+// using http.DefaultClient.
+func NewClient(url string) *Client {
+	return &Client{url: strings.TrimSuffix(url, "/"), http: http.DefaultClient}
+}
+
+// WithHTTPClient returns a copy of c sending its requests with h.
+func (c *Client) WithHTTPClient(h *http.Client) *Client {
+	return &Client{url: c.url, http: h}
+}
+
+// StatusError is a failed call. It matches ErrNotFound or ErrInvalid as the
+// server error did.
+type StatusError struct {
+	Status  int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("server: %s: %s", http.StatusText(e.Status), e.Message)
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.Status == http.StatusNotFound
+	case ErrInvalid:
+		return e.Status == http.StatusBadRequest || e.Status == http.StatusMethodNotAllowed || e.Status == http.StatusRequestEntityTooLarge
+	}
+
+	return false
+}
+
+// Prove returns a proof of circuit with witness.
+func (c *Client) Prove(ctx context.Context, circuit string, witness []byte) ([]byte, error) {
+	var resp ProveResponse
+	if err := c.call(ctx, http.MethodPost, "/v1/prove", &ProveRequest{Circuit: circuit, Witness: witness}, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Proof, nil
+}
+
+// Msm returns the MSM of scalars with points.
+func (c *Client) Msm(ctx context.Context, curve ecc.ID, points, scalars []byte) ([]byte, error) {
+	var resp MsmResponse
+	if err := c.call(ctx, http.MethodPost, "/v1/msm", &MsmRequest{Curve: curve.String(), Points: points, Scalars: scalars}, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Point, nil
+}
+
+// MsmKey returns the MSM of scalars with the first points of a key loaded on
+// the server.
+func (c *Client) MsmKey(ctx context.Context, curve ecc.ID, key string, scalars []byte) ([]byte, error) {
+	var resp MsmResponse
+	if err := c.call(ctx, http.MethodPost, "/v1/msm", &MsmRequest{Curve: curve.String(), Key: key, Scalars: scalars}, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Point, nil
+}
+
+// Ntt returns the NTT of values, see Engine.Ntt.
+func (c *Client) Ntt(ctx context.Context, curve ecc.ID, values []byte, inverse, coset bool) ([]byte, error) {
+	var resp NttResponse
+	if err := c.call(ctx, http.MethodPost, "/v1/ntt", &NttRequest{Curve: curve.String(), Values: values, Inverse: inverse, Coset: coset}, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Values, nil
+}
+
+// Status returns the curves and circuits served.
+func (c *Client) Status(ctx context.Context) (*Status, error) {
+	var resp Status
+	if err := c.call(ctx, http.MethodGet, "/v1/status", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (c *Client) call(ctx context.Context, method, path string, req, resp any) error {
+	var body bytes.Buffer
+	if req != nil {
+		if err := json.NewEncoder(&body).Encode(req); err != nil {
+			return err
+		}
+	}
+
+	r, err := http.NewRequestWithContext(ctx, method, c.url+path, &body)
+	if err != nil {
+		return err
+	}
+	if req != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := c.http.Do(r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		var e ErrorResponse
+		if err := json.NewDecoder(res.Body).Decode(&e); err != nil {
+			e.Error = err.Error()
+		}
+		return &StatusError{Status: res.StatusCode, Message: e.Error}
+	}
+
+	return json.NewDecoder(res.Body).Decode(resp)
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+type scalar[T any] interface {
+	*T
+	Marshal() []byte
+	SetBytesCanonical(e []byte) error
+}
+
+type affine[T, Jac any] interface {
+	*T
+	Marshal() []byte
+	SetBytes(buf []byte) (int, error)
+	FromJacobian(p *Jac) *T
+}
+
+type jacobian[T, Affine, Fr any] interface {
+	*T
+	MultiExp(points []Affine, scalars []Fr, config ecc.MultiExpConfig) (*T, error)
+}
+
+// curve holds what the engines of one curve need from gnark-crypto: the wire
+// encoding, a host MSM and NTT, and the coset powers of the device NTT.
+type curve[Fr, G1Affine, G1Jac any] struct {
+	id          ecc.ID
+	scalarBytes int
+	pointBytes  int
+
+	decodeScalar func(b []byte, s *Fr) error
+	encodeScalar func(s *Fr) []byte
+	decodePoint  func(b []byte, p *G1Affine) error
+	encodePoint  func(p *G1Jac) []byte
+	msm          func(points []G1Affine, scalars []Fr) (G1Jac, error)
+	// ntt transforms values in place, natural order in and out
+	ntt func(values []Fr, inverse, coset bool)
+	// cosetPowers returns g^i, or g^-i if inverse is set, for i < n
+	cosetPowers func(n int, inverse bool) []Fr
+}
+
+func newCurve[Fr, G1Affine, G1Jac any, PFr scalar[Fr], PG1 affine[G1Affine, G1Jac], PJac jacobian[G1Jac, G1Affine, Fr]](id ecc.ID, ntt func(values []Fr, inverse, coset bool), cosetPowers func(n int, inverse bool) []Fr) *curve[Fr, G1Affine, G1Jac] {
+	return &curve[Fr, G1Affine, G1Jac]{
+		id:          id,
+		scalarBytes: len(PFr(new(Fr)).Marshal()),
+		pointBytes:  len(PG1(new(G1Affine)).Marshal()),
+		decodeScalar: func(b []byte, s *Fr) error {
+			return PFr(s).SetBytesCanonical(b)
+		},
+		encodeScalar: func(s *Fr) []byte {
+			return PFr(s).Marshal()
+		},
+		decodePoint: func(b []byte, p *G1Affine) error {
+			_, err := PG1(p).SetBytes(b)
+			return err
+		},
+		encodePoint: func(p *G1Jac) []byte {
+			var a G1Affine
+			PG1(&a).FromJacobian(p)
+			return PG1(&a).Marshal()
+		},
+		msm: func(points []G1Affine, scalars []Fr) (res G1Jac, err error) {
+			_, err = PJac(&res).MultiExp(points, scalars, ecc.MultiExpConfig{})
+			return
+		},
+		ntt:         ntt,
+		cosetPowers: cosetPowers,
+	}
+}
+
+var curves = make(map[ecc.ID]any)
+
+func register[Fr, G1Affine, G1Jac any](c *curve[Fr, G1Affine, G1Jac]) {
+	curves[c.id] = c
+	hosts[c.id] = func() Engine { return newEngine[Fr, G1Affine, G1Jac](c, host[Fr, G1Affine, G1Jac]{c}) }
+}
+
+func (c *curve[Fr, G1Affine, G1Jac]) scalars(b []byte) ([]Fr, error) {
+	if len(b)%c.scalarBytes != 0 {
+		return nil, fmt.Errorf("%w: %d bytes of scalars, not a multiple of %d", ErrInvalid, len(b), c.scalarBytes)
+	}
+
+	res := make([]Fr, len(b)/c.scalarBytes)
+	for i := range res {
+		if err := c.decodeScalar(b[i*c.scalarBytes:(i+1)*c.scalarBytes], &res[i]); err != nil {
+			return nil, fmt.Errorf("%w: scalar %d: %v", ErrInvalid, i, err)
+		}
+	}
+
+	return res, nil
+}
+
+func (c *curve[Fr, G1Affine, G1Jac]) encodeScalars(values []Fr) []byte {
+	res := make([]byte, 0, len(values)*c.scalarBytes)
+	for i := range values {
+		res = append(res, c.encodeScalar(&values[i])...)
+	}
+
+	return res
+}
+
+// points decodes b and checks that the points are on the curve and in the
+// subgroup, which the SetBytes of gnark-crypto does unless told otherwise.
+func (c *curve[Fr, G1Affine, G1Jac]) points(b []byte) ([]G1Affine, error) {
+	if len(b)%c.pointBytes != 0 {
+		return nil, fmt.Errorf("%w: %d bytes of points, not a multiple of %d", ErrInvalid, len(b), c.pointBytes)
+	}
+
+	res := make([]G1Affine, len(b)/c.pointBytes)
+	for i := range res {
+		if err := c.decodePoint(b[i*c.pointBytes:(i+1)*c.pointBytes], &res[i]); err != nil {
+			return nil, fmt.Errorf("%w: point %d: %v", ErrInvalid, i, err)
+		}
+	}
+
+	return res, nil
+}
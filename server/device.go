@@ -0,0 +1,111 @@
+package server
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/ingonyama-zk/iciclegnark"
+)
+
+// device runs on an iciclegnark.Curve; keys stay in device memory.
+type device[Fr, G1Affine, G1Jac, G2Affine, G2Jac any] struct {
+	c     iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]
+	curve *curve[Fr, G1Affine, G1Jac]
+}
+
+// Device returns a new engine running on c. The curve must be one Host
+// supports, whose encoding and coset generator the engine shares.
+func Device[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) (Engine, error) {
+	h, ok := curves[c.ID()]
+	if !ok {
+		return nil, fmt.Errorf("%w: curve %s", ErrNotFound, c.ID())
+	}
+
+	cv, ok := h.(*curve[Fr, G1Affine, G1Jac])
+	if !ok {
+		return nil, fmt.Errorf("server: curve %s does not match the gnark-crypto types", c.ID())
+	}
+
+	return newEngine[Fr, G1Affine, G1Jac](cv, device[Fr, G1Affine, G1Jac, G2Affine, G2Jac]{c: c, curve: cv}), nil
+}
+
+type resident struct {
+	points_d unsafe.Pointer
+}
+
+func (d device[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) load(points []G1Affine) (any, error) {
+	points_d, err := d.c.CopyG1PointsToDevice(points)
+	if err != nil {
+		return nil, err
+	}
+
+	return resident{points_d}, nil
+}
+
+func (d device[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) free(points any) {
+	d.c.FreeDevicePointer(points.(resident).points_d)
+}
+
+func (d device[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) msm(points any, scalars []Fr) (G1Jac, error) {
+	scalars_d, err := d.c.CopyScalarsToDevice(scalars)
+	if err != nil {
+		var zero G1Jac
+		return zero, err
+	}
+	defer d.c.FreeDevicePointer(scalars_d)
+
+	return d.c.MsmOnDevice(scalars_d, points.(resident).points_d, len(scalars))
+}
+
+func (d device[Fr, G1Affine, G1Jac, G2Affine, G2Jac]) ntt(values []Fr, inverse, coset bool) ([]Fr, error) {
+	c, size := d.c, len(values)
+
+	var buffers []unsafe.Pointer
+	defer func() {
+		for _, p := range buffers {
+			c.FreeDevicePointer(p)
+		}
+	}()
+	upload := func(v []Fr) (unsafe.Pointer, error) {
+		p, err := c.CopyScalarsToDevice(v)
+		if err == nil {
+			buffers = append(buffers, p)
+		}
+		return p, err
+	}
+
+	twiddles_d, err := c.GenerateTwiddleFactors(size, inverse)
+	if err != nil {
+		return nil, err
+	}
+	buffers = append(buffers, twiddles_d)
+
+	values_d, err := upload(values)
+	if err != nil {
+		return nil, err
+	}
+
+	var cosetPowers_d unsafe.Pointer
+	if coset {
+		if cosetPowers_d, err = upload(d.curve.cosetPowers(size, inverse)); err != nil {
+			return nil, err
+		}
+	}
+
+	var out_d unsafe.Pointer
+	if inverse {
+		if out_d, err = c.INttOnDevice(values_d, twiddles_d, cosetPowers_d, size, coset); err != nil {
+			return nil, err
+		}
+		buffers = append(buffers, out_d)
+	} else {
+		if out_d, err = upload(make([]Fr, size)); err != nil {
+			return nil, err
+		}
+		if err := c.NttOnDevice(out_d, values_d, twiddles_d, cosetPowers_d, size, size, coset); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.CopyScalarsFromDevice(out_d, size)
+}
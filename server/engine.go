@@ -0,0 +1,186 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Engine runs the operations of one curve on encoded values. Scalars are
+// canonical big-endian field elements (fr.Element.Marshal) and points
+// uncompressed affine points (G1Affine.Marshal), back to back.
+//
+// Engines serialize their operations and are safe for concurrent use.
+type Engine interface {
+	Curve() ecc.ID
+	// LoadKey keeps points under name for MsmKey, on the device for device
+	// engines. Loading a name again replaces its points.
+	// LoadKey, Msm and MsmKey reject empty points or scalars with ErrInvalid.
+	LoadKey(name string, points []byte) error
+	// Msm returns the MSM of scalars with as many points.
+	Msm(points, scalars []byte) ([]byte, error)
+	// MsmKey returns the MSM of scalars with the first points of the key.
+	MsmKey(name string, scalars []byte) ([]byte, error)
+	// Ntt returns the evaluations of the polynomial with coefficients values
+	// over the subgroup of their size, or its coefficients from evaluations
+	// if inverse is set; on the coset shifted by the multiplicative generator
+	// if coset is set. Values are in natural order, their count a power of two.
+	Ntt(values []byte, inverse, coset bool) ([]byte, error)
+	// Close frees the keys.
+	Close() error
+}
+
+var (
+	// ErrNotFound is matched by the errors on unknown curves, keys and
+	// circuits.
+	ErrNotFound = errors.New("server: not found")
+	// ErrInvalid is matched by the errors on malformed requests.
+	ErrInvalid = errors.New("server: invalid request")
+)
+
+// backend runs the operations of an engine on decoded values.
+type backend[Fr, G1Affine, G1Jac any] interface {
+	// load returns the handle of points kept for msm
+	load(points []G1Affine) (any, error)
+	free(points any)
+	msm(points any, scalars []Fr) (G1Jac, error)
+	ntt(values []Fr, inverse, coset bool) ([]Fr, error)
+}
+
+type key struct {
+	points any
+	count  int
+}
+
+type engine[Fr, G1Affine, G1Jac any] struct {
+	curve   *curve[Fr, G1Affine, G1Jac]
+	backend backend[Fr, G1Affine, G1Jac]
+
+	lock sync.Mutex
+	keys map[string]key
+}
+
+func newEngine[Fr, G1Affine, G1Jac any](c *curve[Fr, G1Affine, G1Jac], b backend[Fr, G1Affine, G1Jac]) *engine[Fr, G1Affine, G1Jac] {
+	return &engine[Fr, G1Affine, G1Jac]{curve: c, backend: b, keys: make(map[string]key)}
+}
+
+func (e *engine[Fr, G1Affine, G1Jac]) Curve() ecc.ID { return e.curve.id }
+
+func (e *engine[Fr, G1Affine, G1Jac]) LoadKey(name string, encoded []byte) error {
+	points, err := e.curve.points(encoded)
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		return fmt.Errorf("%w: %s key %q of no points", ErrInvalid, e.curve.id, name)
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	handle, err := e.backend.load(points)
+	if err != nil {
+		return err
+	}
+	if old, ok := e.keys[name]; ok {
+		e.backend.free(old.points)
+	}
+	e.keys[name] = key{points: handle, count: len(points)}
+
+	return nil
+}
+
+func (e *engine[Fr, G1Affine, G1Jac]) Msm(encodedPoints, encodedScalars []byte) ([]byte, error) {
+	points, err := e.curve.points(encodedPoints)
+	if err != nil {
+		return nil, err
+	}
+	scalars, err := e.curve.scalars(encodedScalars)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("%w: empty MSM", ErrInvalid)
+	}
+	if len(points) != len(scalars) {
+		return nil, fmt.Errorf("%w: %d scalars for %d points", ErrInvalid, len(scalars), len(points))
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	handle, err := e.backend.load(points)
+	if err != nil {
+		return nil, err
+	}
+	defer e.backend.free(handle)
+
+	res, err := e.backend.msm(handle, scalars)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.curve.encodePoint(&res), nil
+}
+
+func (e *engine[Fr, G1Affine, G1Jac]) MsmKey(name string, encodedScalars []byte) ([]byte, error) {
+	scalars, err := e.curve.scalars(encodedScalars)
+	if err != nil {
+		return nil, err
+	}
+	if len(scalars) == 0 {
+		return nil, fmt.Errorf("%w: empty MSM", ErrInvalid)
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	k, ok := e.keys[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s key %q", ErrNotFound, e.curve.id, name)
+	}
+	if len(scalars) > k.count {
+		return nil, fmt.Errorf("%w: %d scalars for a key of %d points", ErrInvalid, len(scalars), k.count)
+	}
+
+	res, err := e.backend.msm(k.points, scalars)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.curve.encodePoint(&res), nil
+}
+
+func (e *engine[Fr, G1Affine, G1Jac]) Ntt(encoded []byte, inverse, coset bool) ([]byte, error) {
+	values, err := e.curve.scalars(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(values); n == 0 || n&(n-1) != 0 {
+		return nil, fmt.Errorf("%w: NTT of %d values, want a power of two", ErrInvalid, n)
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	res, err := e.backend.ntt(values, inverse, coset)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.curve.encodeScalars(res), nil
+}
+
+func (e *engine[Fr, G1Affine, G1Jac]) Close() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for name, k := range e.keys {
+		e.backend.free(k.points)
+		delete(e.keys, name)
+	}
+
+	return nil
+}
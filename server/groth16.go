@@ -0,0 +1,85 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/backend/witness"
+	"github.com/consensys/gnark/constraint"
+	cs_bls12377 "github.com/consensys/gnark/constraint/bls12-377"
+	cs_bls12381 "github.com/consensys/gnark/constraint/bls12-381"
+	cs_bn254 "github.com/consensys/gnark/constraint/bn254"
+	cs_bw6761 "github.com/consensys/gnark/constraint/bw6-761"
+)
+
+// Groth16 is the Circuit of a gnark Groth16 proving key: the witness is a
+// full gnark witness (Witness.MarshalBinary) and the proof a gnark proof
+// (Proof.WriteTo).
+//
+// The proof is computed on the host by gnark's prover, which has no device
+// backend here: the engine of the curve is not used, Load makes nothing
+// resident on the device and the proving key stays in host memory. A witness
+// of the wrong size or not satisfying the circuit is an ErrInvalid.
+type Groth16 struct {
+	CS  constraint.ConstraintSystem
+	Key groth16.ProvingKey
+}
+
+func (c *Groth16) Curve() ecc.ID { return c.Key.CurveID() }
+
+func (c *Groth16) Load(Engine) error {
+	if c.CS.Field().Cmp(c.Curve().ScalarField()) != 0 {
+		return fmt.Errorf("%w: %s proving key for a circuit over another field", ErrInvalid, c.Curve())
+	}
+
+	return nil
+}
+
+// Prove checks ctx before it starts; gnark's prover cannot be cancelled.
+func (c *Groth16) Prove(ctx context.Context, _ Engine, encoded []byte) ([]byte, error) {
+	w, err := witness.New(c.Curve().ScalarField())
+	if err != nil {
+		return nil, err
+	}
+	if err := w.UnmarshalBinary(encoded); err != nil {
+		return nil, fmt.Errorf("%w: witness: %v", ErrInvalid, err)
+	}
+	want := c.CS.GetNbPublicVariables() - 1 + c.CS.GetNbSecretVariables()
+	if n := reflect.ValueOf(w.Vector()).Len(); n != want {
+		return nil, fmt.Errorf("%w: witness of %d values, want %d", ErrInvalid, n, want)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	proof, err := groth16.Prove(c.CS, c.Key, w)
+	if unsatisfied(err) {
+		return nil, fmt.Errorf("%w: witness: %v", ErrInvalid, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if _, err := proof.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// unsatisfied reports whether err is the solver of a served curve rejecting
+// the witness.
+func unsatisfied(err error) bool {
+	var bn254 *cs_bn254.UnsatisfiedConstraintError
+	var bls12377 *cs_bls12377.UnsatisfiedConstraintError
+	var bls12381 *cs_bls12381.UnsatisfiedConstraintError
+	var bw6761 *cs_bw6761.UnsatisfiedConstraintError
+
+	return errors.As(err, &bn254) || errors.As(err, &bls12377) || errors.As(err, &bls12381) || errors.As(err, &bw6761)
+}
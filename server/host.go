@@ -0,0 +1,38 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// host runs on gnark-crypto; keys stay in host memory.
+type host[Fr, G1Affine, G1Jac any] struct {
+	curve *curve[Fr, G1Affine, G1Jac]
+}
+
+var hosts = make(map[ecc.ID]func() Engine)
+
+// Host returns a new engine of curve on gnark-crypto, which needs neither
+// CUDA nor a GPU.
+func Host(curve ecc.ID) (Engine, error) {
+	h, ok := hosts[curve]
+	if !ok {
+		return nil, fmt.Errorf("%w: curve %s", ErrNotFound, curve)
+	}
+
+	return h(), nil
+}
+
+func (h host[Fr, G1Affine, G1Jac]) load(points []G1Affine) (any, error) { return points, nil }
+
+func (h host[Fr, G1Affine, G1Jac]) free(any) {}
+
+func (h host[Fr, G1Affine, G1Jac]) msm(points any, scalars []Fr) (G1Jac, error) {
+	return h.curve.msm(points.([]G1Affine)[:len(scalars)], scalars)
+}
+
+func (h host[Fr, G1Affine, G1Jac]) ntt(values []Fr, inverse, coset bool) ([]Fr, error) {
+	h.curve.ntt(values, inverse, coset)
+	return values, nil
+}
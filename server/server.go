@@ -0,0 +1,309 @@
+// Package server shares the engines of one machine, typically one GPU, among
+// many services over HTTP/JSON, and provides the client of that API.
+//
+// A Server holds one Engine per curve, Host on gnark-crypto or Device on an
+// iciclegnark.Curve, and the circuits registered on them. Commitment circuits
+// load their SRS points on the engine when registered, where they stay
+// resident, and answer with a KZG commitment, one MSM on the engine. Groth16
+// circuits prove with gnark on the host and keep their proving keys in host
+// memory; they do not use the engine. It serves
+//
+//	POST /v1/prove  ProveRequest  -> ProveResponse
+//	POST /v1/msm    MsmRequest    -> MsmResponse
+//	POST /v1/ntt    NttRequest    -> NttResponse
+//	GET  /v1/status               -> Status
+//
+// Failed calls answer with an ErrorResponse and a 400, 404, 413 or 500
+// status. Request bodies are limited to DefaultMaxRequestBytes unless
+// SetMaxRequestBytes changes it.
+//
+// With Host engines the server needs neither CUDA nor a GPU, so tests can
+// run it in-process:
+//
+//	e, _ := server.Host(ecc.BN254)
+//	s, _ := server.New([]server.Engine{e})
+//	ts := httptest.NewServer(s)
+//	client := server.NewClient(ts.URL)
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark"
+)
+
+// ProveRequest asks for a proof of circuit with witness, in the encoding of
+// the circuit.
+type ProveRequest struct {
+	Circuit string `json:"circuit"`
+	Witness []byte `json:"witness"`
+}
+
+type ProveResponse struct {
+	Proof []byte `json:"proof"`
+}
+
+// MsmRequest asks for the MSM of scalars with either points or the first
+// points of a loaded key.
+type MsmRequest struct {
+	Curve   string `json:"curve"`
+	Key     string `json:"key,omitempty"`
+	Points  []byte `json:"points,omitempty"`
+	Scalars []byte `json:"scalars"`
+}
+
+type MsmResponse struct {
+	Point []byte `json:"point"`
+}
+
+// NttRequest asks for the NTT of values, see Engine.Ntt.
+type NttRequest struct {
+	Curve   string `json:"curve"`
+	Values  []byte `json:"values"`
+	Inverse bool   `json:"inverse,omitempty"`
+	Coset   bool   `json:"coset,omitempty"`
+}
+
+type NttResponse struct {
+	Values []byte `json:"values"`
+}
+
+// Status lists what a server serves.
+type Status struct {
+	Curves   []string `json:"curves"`
+	Circuits []string `json:"circuits"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// DefaultMaxRequestBytes is the default limit of request bodies, enough for
+// MSMs of 2^22 bn254 points.
+const DefaultMaxRequestBytes = 1 << 30
+
+type circuit struct {
+	Circuit
+	engine Engine
+}
+
+// Server serves the API over its engines. It is an http.Handler.
+type Server struct {
+	engines map[ecc.ID]Engine
+	mux     *http.ServeMux
+	log     *slog.Logger
+	// maxRequest limits request bodies, in bytes
+	maxRequest int64
+
+	lock     sync.RWMutex
+	circuits map[string]circuit
+}
+
+// New returns a server over engines, at most one per curve.
+func New(engines []Engine, opts ...iciclegnark.Option) (*Server, error) {
+	s := &Server{
+		engines:    make(map[ecc.ID]Engine),
+		mux:        http.NewServeMux(),
+		log:        iciclegnark.ApplyOptions(opts...).Logger,
+		maxRequest: DefaultMaxRequestBytes,
+		circuits:   make(map[string]circuit),
+	}
+	for _, e := range engines {
+		if _, ok := s.engines[e.Curve()]; ok {
+			return nil, fmt.Errorf("server: two engines of curve %s", e.Curve())
+		}
+		s.engines[e.Curve()] = e
+	}
+
+	s.mux.HandleFunc("/v1/prove", post(s, s.prove))
+	s.mux.HandleFunc("/v1/msm", post(s, s.msm))
+	s.mux.HandleFunc("/v1/ntt", post(s, s.ntt))
+	s.mux.HandleFunc("/v1/status", s.status)
+
+	return s, nil
+}
+
+// Register serves c under id, after loading its proving key on the engine
+// of its curve.
+func (s *Server) Register(id string, c Circuit) error {
+	e, err := s.engine(c.Curve().String())
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, ok := s.circuits[id]; ok {
+		return fmt.Errorf("server: circuit %q registered twice", id)
+	}
+	if err := c.Load(e); err != nil {
+		return fmt.Errorf("server: loading circuit %q: %w", id, err)
+	}
+	s.circuits[id] = circuit{Circuit: c, engine: e}
+
+	return nil
+}
+
+// SetMaxRequestBytes limits request bodies to n bytes. It must be called
+// before the server serves requests.
+func (s *Server) SetMaxRequestBytes(n int64) {
+	s.maxRequest = n
+}
+
+// Close closes the engines.
+func (s *Server) Close() error {
+	var err error
+	for _, e := range s.engines {
+		err = errors.Join(err, e.Close())
+	}
+
+	return err
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) engine(name string) (Engine, error) {
+	id, err := ecc.IDFromString(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: curve %q", ErrNotFound, name)
+	}
+	e, ok := s.engines[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: curve %s", ErrNotFound, id)
+	}
+
+	return e, nil
+}
+
+func (s *Server) prove(r *http.Request, req *ProveRequest) (*ProveResponse, error) {
+	s.lock.RLock()
+	c, ok := s.circuits[req.Circuit]
+	s.lock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: circuit %q", ErrNotFound, req.Circuit)
+	}
+
+	proof, err := c.Prove(r.Context(), c.engine, req.Witness)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProveResponse{Proof: proof}, nil
+}
+
+func (s *Server) msm(_ *http.Request, req *MsmRequest) (*MsmResponse, error) {
+	e, err := s.engine(req.Curve)
+	if err != nil {
+		return nil, err
+	}
+
+	var point []byte
+	if req.Key != "" {
+		if req.Points != nil {
+			return nil, fmt.Errorf("%w: both a key and points", ErrInvalid)
+		}
+		point, err = e.MsmKey(req.Key, req.Scalars)
+	} else {
+		point, err = e.Msm(req.Points, req.Scalars)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &MsmResponse{Point: point}, nil
+}
+
+func (s *Server) ntt(_ *http.Request, req *NttRequest) (*NttResponse, error) {
+	e, err := s.engine(req.Curve)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := e.Ntt(req.Values, req.Inverse, req.Coset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NttResponse{Values: values}, nil
+}
+
+func (s *Server) status(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		s.fail(w, r, http.StatusMethodNotAllowed, fmt.Errorf("%w: %s %s", ErrInvalid, r.Method, r.URL.Path))
+		return
+	}
+
+	status := Status{Curves: []string{}, Circuits: []string{}}
+	for id := range s.engines {
+		status.Curves = append(status.Curves, id.String())
+	}
+	s.lock.RLock()
+	for id := range s.circuits {
+		status.Circuits = append(status.Circuits, id)
+	}
+	s.lock.RUnlock()
+	sort.Strings(status.Curves)
+	sort.Strings(status.Circuits)
+
+	s.reply(w, r, http.StatusOK, &status)
+}
+
+// post adapts a JSON call to a handler of POST requests.
+func post[Req, Resp any](s *Server, call func(r *http.Request, req *Req) (*Resp, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			s.fail(w, r, http.StatusMethodNotAllowed, fmt.Errorf("%w: %s %s", ErrInvalid, r.Method, r.URL.Path))
+			return
+		}
+
+		var req Req
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxRequest)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				s.fail(w, r, http.StatusRequestEntityTooLarge, fmt.Errorf("%w: body over %d bytes", ErrInvalid, tooLarge.Limit))
+				return
+			}
+			s.fail(w, r, http.StatusBadRequest, fmt.Errorf("%w: %v", ErrInvalid, err))
+			return
+		}
+
+		resp, err := call(r, &req)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			s.fail(w, r, http.StatusNotFound, err)
+		case errors.Is(err, ErrInvalid):
+			s.fail(w, r, http.StatusBadRequest, err)
+		case err != nil:
+			s.fail(w, r, http.StatusInternalServerError, err)
+		default:
+			s.reply(w, r, http.StatusOK, resp)
+		}
+	}
+}
+
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, status int, err error) {
+	s.log.Error("request failed", slog.String("path", r.URL.Path), slog.Int("status", status), slog.Any("error", err))
+
+	s.reply(w, r, status, &ErrorResponse{Error: err.Error()})
+}
+
+// reply writes v as the response. The status is sent already, so a failure
+// to write the body can only be logged.
+func (s *Server) reply(w http.ResponseWriter, r *http.Request, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.log.Error("writing the response failed", slog.String("path", r.URL.Path), slog.Int("status", status), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,338 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+	"github.com/ingonyama-zk/iciclegnark/faults"
+	"github.com/ingonyama-zk/iciclegnark/internal/hostcurve"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomScalars(n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for i := range res {
+		res[i].SetRandom()
+	}
+
+	return res
+}
+
+func randomPoints(n int) []bn254.G1Affine {
+	res := make([]bn254.G1Affine, n)
+	for i := range res {
+		res[i].ScalarMultiplicationBase(big.NewInt(int64(i + 1)))
+	}
+
+	return res
+}
+
+func encode[T any, PT interface {
+	*T
+	Marshal() []byte
+}](values []T) []byte {
+	var res []byte
+	for i := range values {
+		res = append(res, PT(&values[i]).Marshal()...)
+	}
+
+	return res
+}
+
+func expectedMsm(t *testing.T, points []bn254.G1Affine, scalars []fr.Element) []byte {
+	var res bn254.G1Affine
+	_, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{})
+	require.NoError(t, err)
+
+	return res.Marshal()
+}
+
+func newClient(t *testing.T) (*Server, *Client) {
+	e, err := Host(ecc.BN254)
+	require.NoError(t, err)
+	s, err := New([]Engine{e})
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(s)
+	t.Cleanup(func() {
+		ts.Close()
+		s.Close()
+	})
+
+	return s, NewClient(ts.URL)
+}
+
+func TestMsm(t *testing.T) {
+	_, client := newClient(t)
+	ctx := context.Background()
+
+	points, scalars := randomPoints(17), randomScalars(17)
+	res, err := client.Msm(ctx, ecc.BN254, encode(points), encode(scalars))
+	require.NoError(t, err)
+	assert.Equal(t, expectedMsm(t, points, scalars), res)
+
+	_, err = client.Msm(ctx, ecc.BN254, encode(points), encode(scalars[1:]))
+	assert.True(t, errors.Is(err, ErrInvalid))
+	_, err = client.Msm(ctx, ecc.BN254, encode(points), []byte{1, 2, 3})
+	assert.True(t, errors.Is(err, ErrInvalid))
+	_, err = client.Msm(ctx, ecc.BN254, nil, nil)
+	assert.True(t, errors.Is(err, ErrInvalid))
+
+	// a compressed x with no point on the curve fails the decoding
+	bad := encode(points)
+	for i := 0; ; i++ {
+		bad[len(bad)-1] = byte(i)
+		if _, err := new(bn254.G1Affine).SetBytes(bad[len(bad)-32:]); err != nil {
+			break
+		}
+	}
+	_, err = client.Msm(ctx, ecc.BN254, bad, encode(scalars))
+	assert.True(t, errors.Is(err, ErrInvalid))
+	_, err = client.Msm(ctx, ecc.BLS12_381, encode(points), encode(scalars))
+	assert.True(t, errors.Is(err, ErrNotFound))
+	_, err = client.MsmKey(ctx, ecc.BN254, "pk", encode(scalars))
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestMaxRequestBytes(t *testing.T) {
+	s, client := newClient(t)
+	s.SetMaxRequestBytes(1 << 10)
+	ctx := context.Background()
+
+	points, scalars := randomPoints(4), randomScalars(4)
+	_, err := client.Msm(ctx, ecc.BN254, encode(points), encode(scalars))
+	assert.NoError(t, err)
+
+	points, scalars = randomPoints(32), randomScalars(32)
+	_, err = client.Msm(ctx, ecc.BN254, encode(points), encode(scalars))
+	var status *StatusError
+	require.True(t, errors.As(err, &status))
+	assert.Equal(t, 413, status.Status)
+	assert.True(t, errors.Is(err, ErrInvalid))
+}
+
+func TestNtt(t *testing.T) {
+	_, client := newClient(t)
+	ctx := context.Background()
+
+	for _, coset := range []bool{false, true} {
+		values := randomScalars(16)
+		evaluations, err := client.Ntt(ctx, ecc.BN254, encode(values), false, coset)
+		require.NoError(t, err)
+
+		expected := append([]fr.Element{}, values...)
+		var opts []fft.Option
+		if coset {
+			opts = append(opts, fft.OnCoset())
+		}
+		fft.NewDomain(16).FFT(expected, fft.DIF, opts...)
+		fft.BitReverse(expected)
+		assert.Equal(t, encode(expected), evaluations)
+
+		coefficients, err := client.Ntt(ctx, ecc.BN254, evaluations, true, coset)
+		require.NoError(t, err)
+		assert.Equal(t, encode(values), coefficients)
+	}
+
+	_, err := client.Ntt(ctx, ecc.BN254, encode(randomScalars(3)), false, false)
+	assert.True(t, errors.Is(err, ErrInvalid))
+}
+
+func TestProve(t *testing.T) {
+	s, client := newClient(t)
+	ctx := context.Background()
+
+	points := randomPoints(32)
+	require.NoError(t, s.Register("kzg", &Commitment{ID: ecc.BN254, Key: "srs", Points: encode(points)}))
+	assert.Error(t, s.Register("kzg", &Commitment{ID: ecc.BN254, Key: "srs"}))
+	assert.True(t, errors.Is(s.Register("other", &Commitment{ID: ecc.BW6_761, Key: "srs"}), ErrNotFound))
+
+	status, err := client.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &Status{Curves: []string{"bn254"}, Circuits: []string{"kzg"}}, status)
+
+	// the proving key stays loaded, witnesses may be shorter
+	for _, n := range []int{32, 5} {
+		scalars := randomScalars(n)
+		proof, err := client.Prove(ctx, "kzg", encode(scalars))
+		require.NoError(t, err)
+		assert.Equal(t, expectedMsm(t, points[:n], scalars), proof)
+
+		res, err := client.MsmKey(ctx, ecc.BN254, "srs", encode(scalars))
+		require.NoError(t, err)
+		assert.Equal(t, proof, res)
+	}
+
+	_, err = client.Prove(ctx, "kzg", encode(randomScalars(33)))
+	assert.True(t, errors.Is(err, ErrInvalid))
+	_, err = client.Prove(ctx, "groth16", nil)
+	var status404 *StatusError
+	require.True(t, errors.As(err, &status404))
+	assert.Equal(t, 404, status404.Status)
+}
+
+// square proves knowledge of the square root X of Y.
+type square struct {
+	X frontend.Variable
+	Y frontend.Variable `gnark:",public"`
+}
+
+func (c *square) Define(api frontend.API) error {
+	api.AssertIsEqual(api.Mul(c.X, c.X), c.Y)
+	return nil
+}
+
+func TestProveGroth16(t *testing.T) {
+	s, client := newClient(t)
+	ctx := context.Background()
+
+	cs, err := frontend.Compile(ecc.BN254.ScalarField(), r1cs.NewBuilder, &square{})
+	require.NoError(t, err)
+	pk, vk, err := groth16.Setup(cs)
+	require.NoError(t, err)
+	require.NoError(t, s.Register("square", &Groth16{CS: cs, Key: pk}))
+
+	w, err := frontend.NewWitness(&square{X: 3, Y: 9}, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+	encoded, err := w.MarshalBinary()
+	require.NoError(t, err)
+
+	res, err := client.Prove(ctx, "square", encoded)
+	require.NoError(t, err)
+	proof := groth16.NewProof(ecc.BN254)
+	_, err = proof.ReadFrom(bytes.NewReader(res))
+	require.NoError(t, err)
+	public, err := w.Public()
+	require.NoError(t, err)
+	assert.NoError(t, groth16.Verify(proof, vk, public))
+
+	// unsatisfied, truncated and malformed witnesses are rejected
+	w, err = frontend.NewWitness(&square{X: 3, Y: 10}, ecc.BN254.ScalarField())
+	require.NoError(t, err)
+	encoded, err = w.MarshalBinary()
+	require.NoError(t, err)
+	_, err = client.Prove(ctx, "square", encoded)
+	assert.True(t, errors.Is(err, ErrInvalid))
+	public, err = w.Public()
+	require.NoError(t, err)
+	truncated, err := public.MarshalBinary()
+	require.NoError(t, err)
+	_, err = client.Prove(ctx, "square", truncated)
+	assert.True(t, errors.Is(err, ErrInvalid))
+	_, err = client.Prove(ctx, "square", []byte{1, 2, 3})
+	assert.True(t, errors.Is(err, ErrInvalid))
+
+	// a cancelled request is not proven
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = (&Groth16{CS: cs, Key: pk}).Prove(cancelled, nil, encoded)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDeviceKeys(t *testing.T) {
+	c := faults.Wrap[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac](hostcurve.NewBN254())
+	e, err := Device[fr.Element, bn254.G1Affine, bn254.G1Jac, bn254.G2Affine, bn254.G2Jac](c)
+	require.NoError(t, err)
+
+	points, scalars := randomPoints(8), randomScalars(8)
+	require.NoError(t, e.LoadKey("pk", encode(points)))
+	assert.Equal(t, 1, c.Live())
+
+	// only the scalars are uploaded, and freed
+	res, err := e.MsmKey("pk", encode(scalars))
+	require.NoError(t, err)
+	assert.Equal(t, expectedMsm(t, points, scalars), res)
+	assert.Equal(t, 1, c.Live())
+
+	// empty requests never reach the device
+	_, err = e.Msm(nil, nil)
+	assert.True(t, errors.Is(err, ErrInvalid))
+	_, err = e.MsmKey("pk", nil)
+	assert.True(t, errors.Is(err, ErrInvalid))
+	assert.True(t, errors.Is(e.LoadKey("empty", nil), ErrInvalid))
+	assert.Equal(t, 1, c.Live())
+
+	// a failed upload keeps the key
+	c.Inject(faults.FailAllocation(1))
+	_, err = e.MsmKey("pk", encode(scalars))
+	assert.True(t, errors.Is(err, faults.ErrInjected))
+	assert.Equal(t, 1, c.Live())
+
+	require.NoError(t, e.LoadKey("pk", encode(points[:4])))
+	assert.Equal(t, 1, c.Live())
+
+	require.NoError(t, e.Close())
+	assert.Zero(t, c.Live())
+	assert.Zero(t, c.BadFrees())
+}
+
+func TestConcurrentEngine(t *testing.T) {
+	e, err := Host(ecc.BN254)
+	require.NoError(t, err)
+	defer e.Close()
+
+	points := randomPoints(16)
+	require.NoError(t, e.LoadKey("pk", encode(points)))
+
+	const calls, iterations = 8, 5
+	scalars := make([][]fr.Element, calls*iterations)
+	expected := make([][]byte, len(scalars))
+	for i := range scalars {
+		scalars[i] = randomScalars(16)
+		expected[i] = expectedMsm(t, points, scalars[i])
+	}
+
+	// the goroutines only record results and errors, checked once they are
+	// done
+	type result struct{ key, msm, roundTrip []byte }
+	results := make([]result, len(scalars))
+	errs := make(chan error, 4*len(scalars))
+	var wg sync.WaitGroup
+	for g := 0; g < calls; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := g * iterations; i < (g+1)*iterations; i++ {
+				r := &results[i]
+				var err error
+				r.key, err = e.MsmKey("pk", encode(scalars[i]))
+				errs <- err
+				r.msm, err = e.Msm(encode(points), encode(scalars[i]))
+				errs <- err
+				var evaluations []byte
+				if evaluations, err = e.Ntt(encode(scalars[i]), false, false); err == nil {
+					r.roundTrip, err = e.Ntt(evaluations, true, false)
+				}
+				errs <- err
+
+				// keys are replaced under the other calls
+				errs <- e.LoadKey(fmt.Sprint("key", g), encode(points[:4]))
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	for i, r := range results {
+		assert.Equal(t, expected[i], r.key)
+		assert.Equal(t, expected[i], r.msm)
+		assert.Equal(t, encode(scalars[i]), r.roundTrip)
+	}
+}
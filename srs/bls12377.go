@@ -0,0 +1,37 @@
+package srs
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fp"
+)
+
+func init() {
+	layouts[ecc.BLS12_377] = map[string]group{
+		G1: points[bls12377.G1Affine, *bls12377.G1Affine]{
+			size: 2 * fp.Bytes,
+			decode: func(b []byte) (p bls12377.G1Affine, err error) {
+				err = bls12377Elements(b, &p.X, &p.Y)
+				return
+			},
+		},
+		G2: points[bls12377.G2Affine, *bls12377.G2Affine]{
+			size: 4 * fp.Bytes,
+			decode: func(b []byte) (p bls12377.G2Affine, err error) {
+				err = bls12377Elements(b, &p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1)
+				return
+			},
+		},
+	}
+}
+
+// bls12377Elements decodes consecutive little-endian elements of b into dst.
+func bls12377Elements(b []byte, dst ...*fp.Element) (err error) {
+	for i, e := range dst {
+		if *e, err = fp.LittleEndian.Element((*[fp.Bytes]byte)(b[i*fp.Bytes:])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
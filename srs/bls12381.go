@@ -0,0 +1,37 @@
+package srs
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+)
+
+func init() {
+	layouts[ecc.BLS12_381] = map[string]group{
+		G1: points[bls12381.G1Affine, *bls12381.G1Affine]{
+			size: 2 * fp.Bytes,
+			decode: func(b []byte) (p bls12381.G1Affine, err error) {
+				err = bls12381Elements(b, &p.X, &p.Y)
+				return
+			},
+		},
+		G2: points[bls12381.G2Affine, *bls12381.G2Affine]{
+			size: 4 * fp.Bytes,
+			decode: func(b []byte) (p bls12381.G2Affine, err error) {
+				err = bls12381Elements(b, &p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1)
+				return
+			},
+		},
+	}
+}
+
+// bls12381Elements decodes consecutive little-endian elements of b into dst.
+func bls12381Elements(b []byte, dst ...*fp.Element) (err error) {
+	for i, e := range dst {
+		if *e, err = fp.LittleEndian.Element((*[fp.Bytes]byte)(b[i*fp.Bytes:])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
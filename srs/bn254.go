@@ -0,0 +1,37 @@
+package srs
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+)
+
+func init() {
+	layouts[ecc.BN254] = map[string]group{
+		G1: points[bn254.G1Affine, *bn254.G1Affine]{
+			size: 2 * fp.Bytes,
+			decode: func(b []byte) (p bn254.G1Affine, err error) {
+				err = bn254Elements(b, &p.X, &p.Y)
+				return
+			},
+		},
+		G2: points[bn254.G2Affine, *bn254.G2Affine]{
+			size: 4 * fp.Bytes,
+			decode: func(b []byte) (p bn254.G2Affine, err error) {
+				err = bn254Elements(b, &p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1)
+				return
+			},
+		},
+	}
+}
+
+// bn254Elements decodes consecutive little-endian elements of b into dst.
+func bn254Elements(b []byte, dst ...*fp.Element) (err error) {
+	for i, e := range dst {
+		if *e, err = fp.LittleEndian.Element((*[fp.Bytes]byte)(b[i*fp.Bytes:])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
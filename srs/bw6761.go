@@ -0,0 +1,37 @@
+package srs
+
+import (
+	"github.com/consensys/gnark-crypto/ecc"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fp"
+)
+
+func init() {
+	layouts[ecc.BW6_761] = map[string]group{
+		G1: points[bw6761.G1Affine, *bw6761.G1Affine]{
+			size: 2 * fp.Bytes,
+			decode: func(b []byte) (p bw6761.G1Affine, err error) {
+				err = bw6761Elements(b, &p.X, &p.Y)
+				return
+			},
+		},
+		G2: points[bw6761.G2Affine, *bw6761.G2Affine]{
+			size: 2 * fp.Bytes,
+			decode: func(b []byte) (p bw6761.G2Affine, err error) {
+				err = bw6761Elements(b, &p.X, &p.Y)
+				return
+			},
+		},
+	}
+}
+
+// bw6761Elements decodes consecutive little-endian elements of b into dst.
+func bw6761Elements(b []byte, dst ...*fp.Element) (err error) {
+	for i, e := range dst {
+		if *e, err = fp.LittleEndian.Element((*[fp.Bytes]byte)(b[i*fp.Bytes:])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
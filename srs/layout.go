@@ -0,0 +1,114 @@
+package srs
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+type gnarkPoint[T any] interface {
+	*T
+	Marshal() []byte
+	IsOnCurve() bool
+	IsInSubGroup() bool
+}
+
+// group decodes points of one group from the device layout.
+type group interface {
+	pointBytes() int
+	// check decodes b and returns its gnark-crypto uncompressed encoding.
+	check(b []byte) ([]byte, error)
+}
+
+type points[T any, PT gnarkPoint[T]] struct {
+	size   int
+	decode func(b []byte) (T, error)
+}
+
+func (p points[T, PT]) pointBytes() int { return p.size }
+
+func (p points[T, PT]) check(b []byte) ([]byte, error) {
+	point, err := p.decode(b)
+	if err != nil {
+		return nil, err
+	}
+	if !PT(&point).IsOnCurve() {
+		return nil, fmt.Errorf("point is not on the curve")
+	}
+	if !PT(&point).IsInSubGroup() {
+		return nil, fmt.Errorf("point is not in the subgroup")
+	}
+
+	return PT(&point).Marshal(), nil
+}
+
+var layouts = make(map[ecc.ID]map[string]group)
+
+func groupOf(curve ecc.ID, name string) (group, error) {
+	g, ok := layouts[curve][name]
+	if !ok {
+		return nil, fmt.Errorf("srs: unsupported curve %s or group %q", curve, name)
+	}
+
+	return g, nil
+}
+
+// PointBytes is the size of a point of group in the device layout of curve.
+func PointBytes(curve ecc.ID, group string) (int, error) {
+	g, err := groupOf(curve, group)
+	if err != nil {
+		return 0, err
+	}
+
+	return g.pointBytes(), nil
+}
+
+// Point decodes point i of raw, device layout points of group, checks it is
+// on the curve and in the subgroup, and returns its gnark-crypto
+// uncompressed encoding (Marshal).
+func Point(curve ecc.ID, group string, raw []byte, i int) ([]byte, error) {
+	g, err := groupOf(curve, group)
+	if err != nil {
+		return nil, err
+	}
+
+	size := g.pointBytes()
+	if i < 0 || (i+1)*size > len(raw) {
+		return nil, fmt.Errorf("srs: point %d out of %d", i, len(raw)/size)
+	}
+
+	encoded, err := g.check(raw[i*size : (i+1)*size])
+	if err != nil {
+		return nil, fmt.Errorf("srs: point %d: %w", i, err)
+	}
+
+	return encoded, nil
+}
+
+// Verify checks that raw, device layout points of group, decodes to points
+// exactly.
+func Verify[T any, PT interface {
+	*T
+	Marshal() []byte
+}](curve ecc.ID, group string, raw []byte, points []T) error {
+	size, err := PointBytes(curve, group)
+	if err != nil {
+		return err
+	}
+	if len(raw) != len(points)*size {
+		return fmt.Errorf("srs: %d bytes for %d points of %d bytes", len(raw), len(points), size)
+	}
+
+	for i := range points {
+		encoded, err := Point(curve, group, raw, i)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(encoded, PT(&points[i]).Marshal()) {
+			return fmt.Errorf("srs: %s point %d does not match its source", group, i)
+		}
+	}
+
+	return nil
+}
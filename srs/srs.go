@@ -0,0 +1,297 @@
+// Package srs reads and writes device-ready SRS files: points already in the
+// icicle device layout, preceded by a manifest describing them.
+//
+// A file is laid out as
+//
+//	magic    8 bytes, "ICGSRS" followed by the format version as a big-endian uint16
+//	length   big-endian uint64, length of the manifest
+//	manifest JSON, see Manifest
+//	data     the sections, back to back, at the offsets of the manifest
+//
+// In the device layout a point is its affine coordinates, each a canonical
+// little-endian base field element (for G2 over an extension, A0 then A1).
+// The point at infinity is all zeros.
+package srs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Version is the format version written by Write and accepted by Open.
+const Version = 1
+
+const magic = "ICGSRS"
+
+// Groups of a section.
+const (
+	G1 = "g1"
+	G2 = "g2"
+)
+
+// Manifest describes the content of a file.
+type Manifest struct {
+	Version int    `json:"version"`
+	Curve   string `json:"curve"`
+	// Source is the kind of object the points come from: kzg or groth16.
+	Source   string    `json:"source"`
+	Sections []Section `json:"sections"`
+}
+
+// Section is one array of points. Offset is relative to the start of the data.
+type Section struct {
+	Name       string `json:"name"`
+	Group      string `json:"group"`
+	Count      int    `json:"count"`
+	PointBytes int    `json:"point_bytes"`
+	Offset     int64  `json:"offset"`
+	Length     int64  `json:"length"`
+	SHA256     string `json:"sha256"`
+}
+
+// Data is the content of a section to write.
+type Data struct {
+	Name  string
+	Group string
+	// Points in the device layout.
+	Points []byte
+}
+
+// Bytes returns the memory of v, for device points converted by a curve
+// package, e.g. BatchConvertFromG1Affine.
+func Bytes[T any](v []T) []byte {
+	if len(v) == 0 {
+		return nil
+	}
+
+	return unsafe.Slice((*byte)(unsafe.Pointer(&v[0])), len(v)*int(unsafe.Sizeof(v[0])))
+}
+
+// Write writes a file of curve holding sections and returns its manifest.
+func Write(w io.Writer, curve ecc.ID, source string, sections []Data) (*Manifest, error) {
+	m := &Manifest{Version: Version, Curve: curve.String(), Source: source}
+
+	var offset int64
+	for _, d := range sections {
+		size, err := PointBytes(curve, d.Group)
+		if err != nil {
+			return nil, err
+		}
+		if len(d.Points)%size != 0 {
+			return nil, fmt.Errorf("srs: section %s: %d bytes is not a multiple of %d", d.Name, len(d.Points), size)
+		}
+
+		sum := sha256.Sum256(d.Points)
+		m.Sections = append(m.Sections, Section{
+			Name:       d.Name,
+			Group:      d.Group,
+			Count:      len(d.Points) / size,
+			PointBytes: size,
+			Offset:     offset,
+			Length:     int64(len(d.Points)),
+			SHA256:     hex.EncodeToString(sum[:]),
+		})
+		offset += int64(len(d.Points))
+	}
+
+	manifest, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var header [len(magic) + 2 + 8]byte
+	copy(header[:], magic)
+	binary.BigEndian.PutUint16(header[len(magic):], Version)
+	binary.BigEndian.PutUint64(header[len(magic)+2:], uint64(len(manifest)))
+
+	if _, err := w.Write(header[:]); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(manifest); err != nil {
+		return nil, err
+	}
+	for _, d := range sections {
+		if _, err := w.Write(d.Points); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// File is an open SRS file.
+type File struct {
+	Manifest
+	r    io.ReaderAt
+	c    io.Closer
+	base int64 // offset of the data
+}
+
+// Open reads the manifest of the file at path.
+func Open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := NewFile(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	file.c = f
+
+	return file, nil
+}
+
+// NewFile reads the manifest of a file held by r.
+func NewFile(r io.ReaderAt) (*File, error) {
+	var header [len(magic) + 2 + 8]byte
+	if _, err := r.ReadAt(header[:], 0); err != nil {
+		return nil, fmt.Errorf("srs: reading header: %w", err)
+	}
+	if !bytes.Equal(header[:len(magic)], []byte(magic)) {
+		return nil, errors.New("srs: not an SRS file")
+	}
+	if v := binary.BigEndian.Uint16(header[len(magic):]); v != Version {
+		return nil, fmt.Errorf("srs: version %d, want %d", v, Version)
+	}
+
+	length := binary.BigEndian.Uint64(header[len(magic)+2:])
+	if length > 1<<24 {
+		return nil, fmt.Errorf("srs: manifest of %d bytes", length)
+	}
+	manifest := make([]byte, length)
+	if _, err := r.ReadAt(manifest, int64(len(header))); err != nil {
+		return nil, fmt.Errorf("srs: reading manifest: %w", err)
+	}
+
+	f := &File{r: r, base: int64(len(header)) + int64(length)}
+	if err := json.Unmarshal(manifest, &f.Manifest); err != nil {
+		return nil, fmt.Errorf("srs: manifest: %w", err)
+	}
+	if f.Version != Version {
+		return nil, fmt.Errorf("srs: manifest version %d, want %d", f.Version, Version)
+	}
+
+	curve, err := f.ID()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range f.Sections {
+		size, err := PointBytes(curve, s.Group)
+		if err != nil {
+			return nil, err
+		}
+		if s.PointBytes != size || s.Length != int64(s.Count)*int64(size) {
+			return nil, fmt.Errorf("srs: section %s: %d points of %d bytes in %d bytes, want %d bytes points", s.Name, s.Count, s.PointBytes, s.Length, size)
+		}
+	}
+
+	return f, nil
+}
+
+// ID returns the curve of the file.
+func (f *File) ID() (ecc.ID, error) {
+	id, err := ecc.IDFromString(f.Curve)
+	if err != nil {
+		return ecc.UNKNOWN, fmt.Errorf("srs: curve %q: %w", f.Curve, err)
+	}
+
+	return id, nil
+}
+
+// Close closes the file opened by Open.
+func (f *File) Close() error {
+	if f.c == nil {
+		return nil
+	}
+
+	return f.c.Close()
+}
+
+func (f *File) section(name string) (Section, error) {
+	for _, s := range f.Sections {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+
+	return Section{}, fmt.Errorf("srs: no section %s", name)
+}
+
+// Section reads the points of the named section and checks their hash.
+func (f *File) Section(name string) ([]byte, error) {
+	s, err := f.section(name)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]byte, s.Length)
+	if _, err := f.r.ReadAt(points, f.base+s.Offset); err != nil {
+		return nil, fmt.Errorf("srs: reading section %s: %w", name, err)
+	}
+
+	sum := sha256.Sum256(points)
+	if hex.EncodeToString(sum[:]) != s.SHA256 {
+		return nil, fmt.Errorf("srs: section %s does not match its hash", name)
+	}
+
+	return points, nil
+}
+
+// SpotCheck decodes samples points of the named section, evenly spaced and
+// including the first and last, and checks they are on the curve and in the
+// subgroup. The section hash is checked too.
+func (f *File) SpotCheck(name string, samples int) error {
+	s, err := f.section(name)
+	if err != nil {
+		return err
+	}
+	points, err := f.Section(name)
+	if err != nil {
+		return err
+	}
+	curve, err := f.ID()
+	if err != nil {
+		return err
+	}
+
+	for _, i := range spread(s.Count, samples) {
+		if _, err := Point(curve, s.Group, points, i); err != nil {
+			return fmt.Errorf("srs: section %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// spread returns up to samples indices in [0, count), evenly spaced.
+func spread(count, samples int) []int {
+	if count == 0 || samples < 1 {
+		return nil
+	}
+	if samples >= count {
+		samples = count
+	}
+	if samples == 1 {
+		return []int{0}
+	}
+
+	indices := make([]int, samples)
+	for i := range indices {
+		indices[i] = i * (count - 1) / (samples - 1)
+	}
+
+	return indices
+}
@@ -0,0 +1,201 @@
+package srs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/kzg"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	bw6761fp "github.com/consensys/gnark-crypto/ecc/bw6-761/fp"
+	bw6761kzg "github.com/consensys/gnark-crypto/ecc/bw6-761/kzg"
+	"github.com/stretchr/testify/assert"
+)
+
+// limbs lays out canonical limbs the way the icicle conversions do.
+func limbs(words ...[]uint64) []byte {
+	var b []byte
+	for _, w := range words {
+		for _, limb := range w {
+			b = binary.LittleEndian.AppendUint64(b, limb)
+		}
+	}
+
+	return b
+}
+
+func bn254G1(points []bn254.G1Affine) []byte {
+	var b []byte
+	for _, p := range points {
+		x, y := p.X.Bits(), p.Y.Bits()
+		b = append(b, limbs(x[:], y[:])...)
+	}
+
+	return b
+}
+
+func bn254G2(points []bn254.G2Affine) []byte {
+	var b []byte
+	for _, p := range points {
+		x0, x1, y0, y1 := p.X.A0.Bits(), p.X.A1.Bits(), p.Y.A0.Bits(), p.Y.A1.Bits()
+		b = append(b, limbs(x0[:], x1[:], y0[:], y1[:])...)
+	}
+
+	return b
+}
+
+func bw6761G1(points []bw6761.G1Affine) []byte {
+	var b []byte
+	for _, p := range points {
+		x, y := p.X.Bits(), p.Y.Bits()
+		b = append(b, limbs(x[:], y[:])...)
+	}
+
+	return b
+}
+
+func testSRS(t *testing.T) (*kzg.SRS, []Data) {
+	srs, err := kzg.NewSRS(8, big.NewInt(42))
+	assert.NoError(t, err)
+
+	return srs, []Data{
+		{Name: "pk.g1", Group: G1, Points: bn254G1(srs.Pk.G1)},
+		{Name: "vk.g2", Group: G2, Points: bn254G2(srs.Vk.G2[:])},
+	}
+}
+
+func TestPointBytes(t *testing.T) {
+	for _, c := range []struct {
+		curve  ecc.ID
+		g1, g2 int
+	}{
+		{ecc.BN254, 64, 128},
+		{ecc.BLS12_377, 96, 192},
+		{ecc.BLS12_381, 96, 192},
+		{ecc.BW6_761, 192, 192},
+	} {
+		g1, err := PointBytes(c.curve, G1)
+		assert.NoError(t, err)
+		assert.Equal(t, c.g1, g1, c.curve)
+
+		g2, err := PointBytes(c.curve, G2)
+		assert.NoError(t, err)
+		assert.Equal(t, c.g2, g2, c.curve)
+	}
+
+	_, err := PointBytes(ecc.SECP256K1, G1)
+	assert.Error(t, err)
+	_, err = PointBytes(ecc.BN254, "g3")
+	assert.Error(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	srs, data := testSRS(t)
+
+	assert.NoError(t, Verify(ecc.BN254, G1, data[0].Points, srs.Pk.G1))
+	assert.NoError(t, Verify(ecc.BN254, G2, data[1].Points, srs.Vk.G2[:]))
+
+	// points in the wrong order
+	swapped := append([]bn254.G1Affine{}, srs.Pk.G1...)
+	swapped[1], swapped[2] = swapped[2], swapped[1]
+	assert.ErrorContains(t, Verify(ecc.BN254, G1, data[0].Points, swapped), "point 1 does not match")
+
+	// not on the curve
+	raw := append([]byte{}, data[0].Points...)
+	raw[64] ^= 1
+	assert.ErrorContains(t, Verify(ecc.BN254, G1, raw, srs.Pk.G1), "not on the curve")
+
+	// not a canonical field element
+	for i := 0; i < fp.Bytes; i++ {
+		raw[64+i] = 0xff
+	}
+	assert.ErrorContains(t, Verify(ecc.BN254, G1, raw, srs.Pk.G1), "point 1")
+
+	assert.Error(t, Verify(ecc.BN254, G1, data[0].Points[:64], srs.Pk.G1))
+
+	// infinity is all zeros
+	assert.NoError(t, Verify(ecc.BN254, G1, make([]byte, 64), []bn254.G1Affine{{}}))
+}
+
+func TestVerifyBW6761(t *testing.T) {
+	srs, err := bw6761kzg.NewSRS(4, big.NewInt(7))
+	assert.NoError(t, err)
+
+	raw := bw6761G1(srs.Pk.G1)
+	assert.Len(t, raw, len(srs.Pk.G1)*2*bw6761fp.Bytes)
+	assert.NoError(t, Verify(ecc.BW6_761, G1, raw, srs.Pk.G1))
+}
+
+func TestWriteOpen(t *testing.T) {
+	srs, data := testSRS(t)
+
+	var buf bytes.Buffer
+	m, err := Write(&buf, ecc.BN254, "kzg", data)
+	assert.NoError(t, err)
+	assert.Equal(t, "bn254", m.Curve)
+	assert.Equal(t, len(srs.Pk.G1), m.Sections[0].Count)
+	assert.Equal(t, 2, m.Sections[1].Count)
+	assert.Equal(t, m.Sections[0].Length, m.Sections[1].Offset)
+
+	f, err := NewFile(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.Equal(t, *m, f.Manifest)
+
+	g2, err := f.Section("vk.g2")
+	assert.NoError(t, err)
+	assert.Equal(t, data[1].Points, g2)
+	assert.NoError(t, f.SpotCheck("pk.g1", 4))
+	assert.NoError(t, f.SpotCheck("vk.g2", 100))
+
+	_, err = f.Section("pk.g2")
+	assert.Error(t, err)
+	assert.NoError(t, f.Close())
+}
+
+func TestCorruption(t *testing.T) {
+	_, data := testSRS(t)
+
+	var buf bytes.Buffer
+	_, err := Write(&buf, ecc.BN254, "kzg", data)
+	assert.NoError(t, err)
+
+	// a flipped bit in the data breaks the hash
+	corrupt := append([]byte{}, buf.Bytes()...)
+	corrupt[len(corrupt)-1] ^= 1
+	f, err := NewFile(bytes.NewReader(corrupt))
+	assert.NoError(t, err)
+	_, err = f.Section("vk.g2")
+	assert.ErrorContains(t, err, "hash")
+	assert.NoError(t, f.SpotCheck("pk.g1", 2))
+
+	// a point off the curve with a matching hash is caught by the spot check
+	data[0].Points[0] ^= 1
+	buf.Reset()
+	_, err = Write(&buf, ecc.BN254, "kzg", data)
+	assert.NoError(t, err)
+	f, err = NewFile(bytes.NewReader(buf.Bytes()))
+	assert.NoError(t, err)
+	assert.ErrorContains(t, f.SpotCheck("pk.g1", 2), "point 0")
+
+	_, err = NewFile(bytes.NewReader([]byte("not an srs file at all")))
+	assert.ErrorContains(t, err, "not an SRS file")
+
+	_, err = Write(&buf, ecc.BN254, "kzg", []Data{{Name: "pk.g1", Group: G1, Points: make([]byte, 10)}})
+	assert.Error(t, err)
+}
+
+func TestSpread(t *testing.T) {
+	assert.Equal(t, []int{0, 3, 6, 9}, spread(10, 4))
+	assert.Equal(t, []int{0, 1, 2}, spread(3, 10))
+	assert.Equal(t, []int{0}, spread(5, 1))
+	assert.Empty(t, spread(0, 4))
+}
+
+func TestBytes(t *testing.T) {
+	assert.Equal(t, []byte{1, 0, 2, 0}, Bytes([]uint16{1, 2}))
+	assert.Nil(t, Bytes([]uint16{}))
+}
@@ -0,0 +1,64 @@
+package stream
+
+import (
+	"unsafe"
+
+	"github.com/ingonyama-zk/iciclegnark"
+)
+
+// CopyScalarsToDevice enqueues an upload of scalars.
+func CopyScalarsToDevice[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](s *Stream, c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], scalars []Fr) *Future[unsafe.Pointer] {
+	return Go(s, func() (unsafe.Pointer, error) { return c.CopyScalarsToDevice(scalars) })
+}
+
+// CopyScalarsFromDevice enqueues a download of size scalars from scalars_d.
+func CopyScalarsFromDevice[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](s *Stream, c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], scalars_d *Future[unsafe.Pointer], size int) *Future[[]Fr] {
+	return Then(s, scalars_d, func(p unsafe.Pointer) ([]Fr, error) { return c.CopyScalarsFromDevice(p, size) })
+}
+
+// MsmOnDevice enqueues a G1 MSM once both inputs are on device.
+func MsmOnDevice[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](s *Stream, c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], scalars_d, points_d *Future[unsafe.Pointer], count int) *Future[G1Jac] {
+	return Go(s, func() (G1Jac, error) {
+		return c.MsmOnDevice(scalars_d.value, points_d.value, count)
+	}, scalars_d, points_d)
+}
+
+// MsmG2OnDevice is the G2 counterpart of MsmOnDevice.
+func MsmG2OnDevice[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](s *Stream, c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], scalars_d, points_d *Future[unsafe.Pointer], count int) *Future[G2Jac] {
+	return Go(s, func() (G2Jac, error) {
+		return c.MsmG2OnDevice(scalars_d.value, points_d.value, count)
+	}, scalars_d, points_d)
+}
+
+// NttOnDevice enqueues a forward NTT of scalars_d into scalars_out and
+// resolves to scalars_out.
+func NttOnDevice[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](s *Stream, c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], scalars_out unsafe.Pointer, scalars_d *Future[unsafe.Pointer], twiddles_d, coset_powers_d unsafe.Pointer, size, twid_size int, isCoset bool) *Future[unsafe.Pointer] {
+	return Then(s, scalars_d, func(in unsafe.Pointer) (unsafe.Pointer, error) {
+		return scalars_out, c.NttOnDevice(scalars_out, in, twiddles_d, coset_powers_d, size, twid_size, isCoset)
+	})
+}
+
+// INttOnDevice enqueues an inverse NTT of scalars_d and resolves to the
+// buffer holding the coefficients.
+func INttOnDevice[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](s *Stream, c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], scalars_d *Future[unsafe.Pointer], twiddles_d, cosetPowers_d unsafe.Pointer, size int, isCoset bool) *Future[unsafe.Pointer] {
+	return Then(s, scalars_d, func(in unsafe.Pointer) (unsafe.Pointer, error) {
+		return c.INttOnDevice(in, twiddles_d, cosetPowers_d, size, isCoset)
+	})
+}
+
+// PolyOps enqueues a = (a*b - c) * den once every operand is on device and
+// resolves to a.
+func PolyOps[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](s *Stream, c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], a_d, b_d, c_d, den_d *Future[unsafe.Pointer], size int) *Future[unsafe.Pointer] {
+	return Go(s, func() (unsafe.Pointer, error) {
+		return a_d.value, c.PolyOps(a_d.value, b_d.value, c_d.value, den_d.value, size)
+	}, a_d, b_d, c_d, den_d)
+}
+
+// FreeDevicePointer enqueues a free of ptr once it is no longer needed by
+// operations already enqueued on s.
+func FreeDevicePointer[Fr, G1Affine, G1Jac, G2Affine, G2Jac any](s *Stream, c iciclegnark.Curve[Fr, G1Affine, G1Jac, G2Affine, G2Jac], ptr *Future[unsafe.Pointer]) *Future[struct{}] {
+	return Then(s, ptr, func(p unsafe.Pointer) (struct{}, error) {
+		c.FreeDevicePointer(p)
+		return struct{}{}, nil
+	})
+}
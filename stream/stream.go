@@ -0,0 +1,179 @@
+// Package stream runs device operations asynchronously, in the manner of CUDA
+// streams: operations enqueued on one Stream run one at a time in enqueue
+// order, operations on different streams may overlap, and an operation can
+// wait on events from other streams.
+//
+// Every enqueue returns a Future. Passing a Future as the input of another
+// operation makes the second wait for the first and hands it the result
+// directly, so a device buffer produced by an NTT can feed an MSM without a
+// round trip through the host.
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// ErrPanic is matched by every PanicError.
+var ErrPanic = errors.New("stream: operation panicked")
+
+// PanicError is the error of an operation that panicked.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("stream: operation panicked: %v", e.Value)
+}
+
+func (e *PanicError) Is(target error) bool { return target == ErrPanic }
+
+// Event completes once and carries the error of the operation it tracks.
+type Event interface {
+	Done() <-chan struct{}
+	Err() error
+}
+
+// Future is the result of an enqueued operation.
+type Future[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+// Ready returns a completed Future holding v.
+func Ready[T any](v T) *Future[T] {
+	f := newFuture[T]()
+	f.complete(v, nil)
+
+	return f
+}
+
+func (f *Future[T]) complete(v T, err error) {
+	f.value, f.err = v, err
+	close(f.done)
+}
+
+// Done is closed when the operation has completed.
+func (f *Future[T]) Done() <-chan struct{} { return f.done }
+
+// Err returns the error of the operation, nil until it has completed.
+func (f *Future[T]) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// Wait blocks until the operation completes or ctx is done. Cancelling ctx
+// does not cancel the operation.
+func (f *Future[T]) Wait(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Stream orders the operations enqueued on it.
+type Stream struct {
+	mu   sync.Mutex
+	tail Event
+}
+
+// New returns an empty stream.
+func New() *Stream {
+	return &Stream{tail: Ready(struct{}{})}
+}
+
+// enqueue makes the next operation wait for the previous one.
+func (s *Stream) enqueue(next Event) (previous Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, s.tail = s.tail, next
+
+	return previous
+}
+
+// Go enqueues fn on s. It runs after every operation enqueued before it on s
+// and after deps have completed; if a dependency failed, fn is skipped and
+// the Future carries that error. If fn panics, the Future carries a
+// *PanicError and the stream goes on.
+func Go[T any](s *Stream, fn func() (T, error), deps ...Event) *Future[T] {
+	f := newFuture[T]()
+	previous := s.enqueue(f)
+
+	go func() {
+		<-previous.Done()
+		for _, dep := range deps {
+			<-dep.Done()
+			if err := dep.Err(); err != nil {
+				var zero T
+				f.complete(zero, err)
+				return
+			}
+		}
+
+		f.complete(run(fn))
+	}()
+
+	return f
+}
+
+// run calls fn and turns a panic into a *PanicError.
+func run[T any](fn func() (T, error)) (v T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero T
+			v, err = zero, &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	return fn()
+}
+
+// Then enqueues fn on s with the result of in, once in has completed.
+func Then[T, U any](s *Stream, in *Future[T], fn func(T) (U, error)) *Future[U] {
+	return Go(s, func() (U, error) { return fn(in.value) }, in)
+}
+
+// Record returns an event that completes when every operation enqueued on s
+// so far has completed, for other streams to wait on.
+func (s *Stream) Record() Event {
+	return Go(s, func() (struct{}, error) { return struct{}{}, nil })
+}
+
+// WaitEvent makes operations enqueued on s from now on wait for e.
+func (s *Stream) WaitEvent(e Event) {
+	Go(s, func() (struct{}, error) {
+		<-e.Done()
+		return struct{}{}, nil
+	})
+}
+
+// Synchronize waits for every operation enqueued on s so far.
+func (s *Stream) Synchronize(ctx context.Context) error {
+	s.mu.Lock()
+	tail := s.tail
+	s.mu.Unlock()
+
+	select {
+	case <-tail.Done():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
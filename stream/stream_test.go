@@ -0,0 +1,249 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/ingonyama-zk/iciclegnark/faults"
+	"github.com/ingonyama-zk/iciclegnark/internal/hostcurve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamOrder(t *testing.T) {
+	s := New()
+
+	var mu sync.Mutex
+	var order []int
+	var futures []*Future[int]
+	for i := 0; i < 50; i++ {
+		i := i
+		futures = append(futures, Go(s, func() (int, error) {
+			// later operations finish faster, so only the stream keeps them in order
+			time.Sleep(time.Duration(50-i) * 10 * time.Microsecond)
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			return i, nil
+		}))
+	}
+
+	assert.NoError(t, s.Synchronize(context.Background()))
+	for i, f := range futures {
+		v, err := f.Wait(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, i, v)
+		assert.Equal(t, i, order[i])
+	}
+}
+
+func TestStreamsOverlap(t *testing.T) {
+	a, b := New(), New()
+	started := make(chan struct{})
+
+	// a blocks until b has started, which deadlocks if streams are serialized
+	fa := Go(a, func() (int, error) {
+		<-started
+		return 1, nil
+	})
+	fb := Go(b, func() (int, error) {
+		close(started)
+		return 2, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	va, err := fa.Wait(ctx)
+	assert.NoError(t, err)
+	vb, err := fb.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, va+vb)
+}
+
+func TestDependencies(t *testing.T) {
+	a, b := New(), New()
+	release := make(chan struct{})
+
+	produced := Go(a, func() (int, error) {
+		<-release
+		return 21, nil
+	})
+	consumed := Then(b, produced, func(v int) (int, error) { return 2 * v, nil })
+	unrelated := Go(b, func() (int, error) { return 1, nil })
+
+	select {
+	case <-consumed.Done():
+		t.Fatal("dependent operation ran before its input")
+	case <-time.After(10 * time.Millisecond):
+	}
+	assert.NoError(t, unrelated.Err())
+
+	close(release)
+	v, err := consumed.Wait(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+}
+
+func TestRecordAndWaitEvent(t *testing.T) {
+	a, b := New(), New()
+	release := make(chan struct{})
+	var done bool
+
+	Go(a, func() (int, error) {
+		<-release
+		done = true
+		return 0, nil
+	})
+	b.WaitEvent(a.Record())
+	seen := Go(b, func() (bool, error) { return done, nil })
+
+	close(release)
+	v, err := seen.Wait(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, v)
+}
+
+func TestErrorPropagation(t *testing.T) {
+	s := New()
+	failure := errors.New("failed")
+
+	failed := Go(s, func() (int, error) { return 0, failure })
+	ran := false
+	skipped := Then(s, failed, func(int) (int, error) {
+		ran = true
+		return 1, nil
+	})
+	independent := Go(s, func() (int, error) { return 2, nil })
+
+	_, err := skipped.Wait(context.Background())
+	assert.ErrorIs(t, err, failure)
+	assert.False(t, ran)
+
+	v, err := independent.Wait(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestPanic(t *testing.T) {
+	s := New()
+
+	panicked := Go(s, func() (int, error) { panic("boom") })
+	skipped := Then(s, panicked, func(int) (int, error) { return 1, nil })
+	next := Go(s, func() (int, error) { return 2, nil })
+
+	_, err := panicked.Wait(context.Background())
+	assert.ErrorIs(t, err, ErrPanic)
+	var perr *PanicError
+	if assert.ErrorAs(t, err, &perr) {
+		assert.Equal(t, "boom", perr.Value)
+		assert.NotEmpty(t, perr.Stack)
+	}
+	_, err = skipped.Wait(context.Background())
+	assert.ErrorIs(t, err, ErrPanic)
+
+	v, err := next.Wait(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v)
+}
+
+func TestWaitContext(t *testing.T) {
+	s := New()
+	release := make(chan struct{})
+	f := Go(s, func() (int, error) {
+		<-release
+		return 1, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := f.Wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, s.Synchronize(ctx), context.Canceled)
+	assert.NoError(t, f.Err())
+
+	close(release)
+	v, err := f.Wait(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+func TestPipeline(t *testing.T) {
+	c := hostcurve.NewInts(ecc.SECP256K1)
+	ntt, msm := New(), New()
+
+	// NTT on one stream, feeding an MSM on another
+	scalars_d := CopyScalarsToDevice[int, int, int, int, int](ntt, c, []int{1, 2, 3, 4})
+	out_d := c.Upload(make([]int, 4))
+	evaluations_d := NttOnDevice[int, int, int, int, int](ntt, c, out_d, scalars_d, nil, nil, 4, 4, false)
+	coefficients_d := INttOnDevice[int, int, int, int, int](ntt, c, evaluations_d, nil, nil, 4, false)
+
+	points_d := Go(msm, func() (unsafe.Pointer, error) { return c.CopyG1PointsToDevice([]int{1, 1, 1, 1}) })
+	res := MsmOnDevice[int, int, int, int, int](msm, c, evaluations_d, points_d, 4)
+	resG2 := MsmG2OnDevice[int, int, int, int, int](msm, c, coefficients_d, points_d, 4)
+
+	// PolyOps overwrites the coefficients the G2 MSM reads
+	ntt.WaitEvent(resG2)
+	ones := Ready(c.Upload([]int{1, 1, 1, 1}))
+	twos := Ready(c.Upload([]int{2, 2, 2, 2}))
+	poly_d := PolyOps[int, int, int, int, int](ntt, c, coefficients_d, twos, ones, twos, 4)
+	poly := CopyScalarsFromDevice[int, int, int, int, int](ntt, c, poly_d, 4)
+
+	ctx := context.Background()
+	v, err := res.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1+3+6+10, v)
+
+	v, err = resG2.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, -(1 + 2 + 3 + 4), v)
+
+	p, err := poly.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 6, 10, 14}, p)
+
+	for _, f := range []*Future[unsafe.Pointer]{scalars_d, evaluations_d, coefficients_d, points_d, ones, twos} {
+		FreeDevicePointer[int, int, int, int, int](msm, c, f)
+	}
+	assert.NoError(t, msm.Synchronize(ctx))
+	assert.Zero(t, c.Live())
+}
+
+func TestPipelineFaults(t *testing.T) {
+	c := faults.Wrap[int, int, int, int, int](hostcurve.NewInts(ecc.SECP256K1))
+	c.Inject(faults.FailKernel(faults.NttOnDevice, 3), faults.FailAllocation(3))
+	s := New()
+	ctx := context.Background()
+
+	out_d, err := c.CopyScalarsToDevice(make([]int, 4))
+	assert.NoError(t, err)
+
+	// the failed NTT fails every operation depending on it, but not the others
+	scalars_d := CopyScalarsToDevice[int, int, int, int, int](s, c, []int{1, 2, 3, 4})
+	evaluations_d := NttOnDevice[int, int, int, int, int](s, c, out_d, scalars_d, nil, nil, 4, 4, false)
+	coefficients_d := INttOnDevice[int, int, int, int, int](s, c, evaluations_d, nil, nil, 4, false)
+	points_d := CopyScalarsToDevice[int, int, int, int, int](s, c, []int{1, 1, 1, 1})
+	res := MsmOnDevice[int, int, int, int, int](s, c, scalars_d, points_d, 4)
+
+	_, err = evaluations_d.Wait(ctx)
+	var status *faults.StatusError
+	assert.ErrorAs(t, err, &status)
+	_, err = coefficients_d.Wait(ctx)
+	assert.ErrorAs(t, err, &status)
+
+	// the third allocation is the points upload
+	_, err = res.Wait(ctx)
+	var allocation *faults.AllocationError
+	assert.ErrorAs(t, err, &allocation)
+
+	c.FreeDevicePointer(out_d)
+	for _, f := range []*Future[unsafe.Pointer]{scalars_d, coefficients_d, points_d} {
+		FreeDevicePointer[int, int, int, int, int](s, c, f)
+	}
+	assert.NoError(t, s.Synchronize(ctx))
+	assert.Zero(t, c.Live())
+	assert.Zero(t, c.BadFrees())
+}
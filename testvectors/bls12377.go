@@ -0,0 +1,50 @@
+package testvectors
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr/fft"
+)
+
+type bls12377Curve = curve[fr.Element, bls12377.G1Affine, bls12377.G2Affine, *fr.Element, *bls12377.G1Affine, *bls12377.G2Affine]
+
+var bls12377Suite = &bls12377Curve{
+	id: ecc.BLS12_377,
+	g1Base: func(s *big.Int) (p bls12377.G1Affine) {
+		p.ScalarMultiplicationBase(s)
+		return
+	},
+	g2Base: func(s *big.Int) (p bls12377.G2Affine) {
+		p.ScalarMultiplicationBase(s)
+		return
+	},
+	msmG1: func(points []bls12377.G1Affine, scalars []fr.Element) (p bls12377.G1Affine, err error) {
+		_, err = p.MultiExp(points, scalars, ecc.MultiExpConfig{})
+		return
+	},
+	msmG2: func(points []bls12377.G2Affine, scalars []fr.Element) (p bls12377.G2Affine, err error) {
+		_, err = p.MultiExp(points, scalars, ecc.MultiExpConfig{})
+		return
+	},
+	ntt: func(values []fr.Element, inverse bool) {
+		domain := fft.NewDomain(uint64(len(values)))
+		if inverse {
+			domain.FFTInverse(values, fft.DIF)
+		} else {
+			domain.FFT(values, fft.DIF)
+		}
+		fft.BitReverse(values)
+	},
+}
+
+func init() {
+	suites[ecc.BLS12_377] = bls12377Suite
+}
+
+// DecodeBLS12377 validates v and returns its values as bls12377 types.
+func DecodeBLS12377(v *Vectors) (*Decoded[fr.Element, bls12377.G1Affine, bls12377.G2Affine], error) {
+	return bls12377Suite.decode(v)
+}
@@ -0,0 +1,50 @@
+package testvectors
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+)
+
+type bls12381Curve = curve[fr.Element, bls12381.G1Affine, bls12381.G2Affine, *fr.Element, *bls12381.G1Affine, *bls12381.G2Affine]
+
+var bls12381Suite = &bls12381Curve{
+	id: ecc.BLS12_381,
+	g1Base: func(s *big.Int) (p bls12381.G1Affine) {
+		p.ScalarMultiplicationBase(s)
+		return
+	},
+	g2Base: func(s *big.Int) (p bls12381.G2Affine) {
+		p.ScalarMultiplicationBase(s)
+		return
+	},
+	msmG1: func(points []bls12381.G1Affine, scalars []fr.Element) (p bls12381.G1Affine, err error) {
+		_, err = p.MultiExp(points, scalars, ecc.MultiExpConfig{})
+		return
+	},
+	msmG2: func(points []bls12381.G2Affine, scalars []fr.Element) (p bls12381.G2Affine, err error) {
+		_, err = p.MultiExp(points, scalars, ecc.MultiExpConfig{})
+		return
+	},
+	ntt: func(values []fr.Element, inverse bool) {
+		domain := fft.NewDomain(uint64(len(values)))
+		if inverse {
+			domain.FFTInverse(values, fft.DIF)
+		} else {
+			domain.FFT(values, fft.DIF)
+		}
+		fft.BitReverse(values)
+	},
+}
+
+func init() {
+	suites[ecc.BLS12_381] = bls12381Suite
+}
+
+// DecodeBLS12381 validates v and returns its values as bls12381 types.
+func DecodeBLS12381(v *Vectors) (*Decoded[fr.Element, bls12381.G1Affine, bls12381.G2Affine], error) {
+	return bls12381Suite.decode(v)
+}
@@ -0,0 +1,50 @@
+package testvectors
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr/fft"
+)
+
+type bn254Curve = curve[fr.Element, bn254.G1Affine, bn254.G2Affine, *fr.Element, *bn254.G1Affine, *bn254.G2Affine]
+
+var bn254Suite = &bn254Curve{
+	id: ecc.BN254,
+	g1Base: func(s *big.Int) (p bn254.G1Affine) {
+		p.ScalarMultiplicationBase(s)
+		return
+	},
+	g2Base: func(s *big.Int) (p bn254.G2Affine) {
+		p.ScalarMultiplicationBase(s)
+		return
+	},
+	msmG1: func(points []bn254.G1Affine, scalars []fr.Element) (p bn254.G1Affine, err error) {
+		_, err = p.MultiExp(points, scalars, ecc.MultiExpConfig{})
+		return
+	},
+	msmG2: func(points []bn254.G2Affine, scalars []fr.Element) (p bn254.G2Affine, err error) {
+		_, err = p.MultiExp(points, scalars, ecc.MultiExpConfig{})
+		return
+	},
+	ntt: func(values []fr.Element, inverse bool) {
+		domain := fft.NewDomain(uint64(len(values)))
+		if inverse {
+			domain.FFTInverse(values, fft.DIF)
+		} else {
+			domain.FFT(values, fft.DIF)
+		}
+		fft.BitReverse(values)
+	},
+}
+
+func init() {
+	suites[ecc.BN254] = bn254Suite
+}
+
+// DecodeBN254 validates v and returns its values as bn254 types.
+func DecodeBN254(v *Vectors) (*Decoded[fr.Element, bn254.G1Affine, bn254.G2Affine], error) {
+	return bn254Suite.decode(v)
+}
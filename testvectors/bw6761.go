@@ -0,0 +1,50 @@
+package testvectors
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bw6761 "github.com/consensys/gnark-crypto/ecc/bw6-761"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
+	"github.com/consensys/gnark-crypto/ecc/bw6-761/fr/fft"
+)
+
+type bw6761Curve = curve[fr.Element, bw6761.G1Affine, bw6761.G2Affine, *fr.Element, *bw6761.G1Affine, *bw6761.G2Affine]
+
+var bw6761Suite = &bw6761Curve{
+	id: ecc.BW6_761,
+	g1Base: func(s *big.Int) (p bw6761.G1Affine) {
+		p.ScalarMultiplicationBase(s)
+		return
+	},
+	g2Base: func(s *big.Int) (p bw6761.G2Affine) {
+		p.ScalarMultiplicationBase(s)
+		return
+	},
+	msmG1: func(points []bw6761.G1Affine, scalars []fr.Element) (p bw6761.G1Affine, err error) {
+		_, err = p.MultiExp(points, scalars, ecc.MultiExpConfig{})
+		return
+	},
+	msmG2: func(points []bw6761.G2Affine, scalars []fr.Element) (p bw6761.G2Affine, err error) {
+		_, err = p.MultiExp(points, scalars, ecc.MultiExpConfig{})
+		return
+	},
+	ntt: func(values []fr.Element, inverse bool) {
+		domain := fft.NewDomain(uint64(len(values)))
+		if inverse {
+			domain.FFTInverse(values, fft.DIF)
+		} else {
+			domain.FFT(values, fft.DIF)
+		}
+		fft.BitReverse(values)
+	},
+}
+
+func init() {
+	suites[ecc.BW6_761] = bw6761Suite
+}
+
+// DecodeBW6761 validates v and returns its values as bw6761 types.
+func DecodeBW6761(v *Vectors) (*Decoded[fr.Element, bw6761.G1Affine, bw6761.G2Affine], error) {
+	return bw6761Suite.decode(v)
+}
@@ -0,0 +1,3 @@
+package testvectors
+
+//go:generate go run ../cmd/testvectors -seed 1 -size 16 -out testdata
@@ -0,0 +1,377 @@
+// Package testvectors reads, writes and checks deterministic test vectors, so
+// device results can be compared across backends and releases.
+//
+// A vector file is JSON (format version 1):
+//
+//	{
+//	  "version": 1,
+//	  "curve": "bn254",
+//	  "seed": 1,
+//	  "scalars": ["<hex>", ...],
+//	  "g1": ["<hex>", ...],
+//	  "g2": ["<hex>", ...],
+//	  "msm_g1": "<hex>",
+//	  "msm_g2": "<hex>",
+//	  "ntt": ["<hex>", ...],
+//	  "intt": ["<hex>", ...]
+//	}
+//
+// curve is the gnark-crypto ecc.ID name. Scalars are canonical big-endian
+// fr elements and points the uncompressed gnark-crypto encoding
+// (G1Affine.Marshal), all hex encoded. msm_g1 and msm_g2 are the affine
+// results of multiplying g1 and g2 by scalars. ntt and intt are the forward and
+// inverse NTT of scalars, natural order in and out; they are present only when
+// the number of scalars is a power of two.
+package testvectors
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand"
+	"os"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Version is the format version written by Generate and accepted by Read.
+const Version = 1
+
+// Vectors is the encoded content of a vector file.
+type Vectors struct {
+	Version int      `json:"version"`
+	Curve   string   `json:"curve"`
+	Seed    int64    `json:"seed"`
+	Scalars []string `json:"scalars"`
+	G1      []string `json:"g1"`
+	G2      []string `json:"g2"`
+	MsmG1   string   `json:"msm_g1"`
+	MsmG2   string   `json:"msm_g2"`
+	Ntt     []string `json:"ntt,omitempty"`
+	INtt    []string `json:"intt,omitempty"`
+}
+
+// Decoded holds vectors as gnark-crypto values. Ntt and INtt are nil when the
+// file has none.
+type Decoded[Fr, G1Affine, G2Affine any] struct {
+	Scalars []Fr
+	G1      []G1Affine
+	G2      []G2Affine
+	MsmG1   G1Affine
+	MsmG2   G2Affine
+	Ntt     []Fr
+	INtt    []Fr
+}
+
+type suite interface {
+	generate(seed int64, size int) (*Vectors, error)
+	validate(v *Vectors) error
+}
+
+var suites = make(map[ecc.ID]suite)
+
+// Curves lists the curves vectors can be generated for.
+func Curves() []ecc.ID {
+	ids := make([]ecc.ID, 0, len(suites))
+	for id := range suites {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+// Generate draws size scalars, G1 and G2 points from seed and computes the
+// expected outputs on the host. The same seed always gives the same file.
+func Generate(id ecc.ID, seed int64, size int) (*Vectors, error) {
+	s, ok := suites[id]
+	if !ok {
+		return nil, fmt.Errorf("testvectors: unsupported curve %s", id)
+	}
+	if size < 1 {
+		return nil, fmt.Errorf("testvectors: size %d, want at least 1", size)
+	}
+
+	return s.generate(seed, size)
+}
+
+// Read decodes a vector file and validates it.
+func Read(r io.Reader) (*Vectors, error) {
+	var v Vectors
+	if err := json.NewDecoder(r).Decode(&v); err != nil {
+		return nil, fmt.Errorf("testvectors: %w", err)
+	}
+
+	if err := v.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+// Load reads and validates the vector file at path.
+func Load(path string) (*Vectors, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Write encodes v as indented JSON.
+func (v *Vectors) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+// Validate checks the version and curve, decodes every value, checking points
+// are on the curve and in the subgroup, and recomputes the expected outputs.
+func (v *Vectors) Validate() error {
+	if v.Version != Version {
+		return fmt.Errorf("testvectors: version %d, want %d", v.Version, Version)
+	}
+
+	id, err := ecc.IDFromString(v.Curve)
+	if err != nil {
+		return fmt.Errorf("testvectors: curve %q: %w", v.Curve, err)
+	}
+	s, ok := suites[id]
+	if !ok {
+		return fmt.Errorf("testvectors: unsupported curve %s", id)
+	}
+
+	return s.validate(v)
+}
+
+type scalar[T any] interface {
+	*T
+	Marshal() []byte
+	SetBytesCanonical(e []byte) error
+}
+
+type point[T any] interface {
+	*T
+	Marshal() []byte
+	Unmarshal(buf []byte) error
+}
+
+// curve implements suite from the few operations that differ between
+// gnark-crypto curve packages.
+type curve[Fr, G1Affine, G2Affine any, PFr scalar[Fr], PG1 point[G1Affine], PG2 point[G2Affine]] struct {
+	id     ecc.ID
+	g1Base func(s *big.Int) G1Affine
+	g2Base func(s *big.Int) G2Affine
+	msmG1  func(points []G1Affine, scalars []Fr) (G1Affine, error)
+	msmG2  func(points []G2Affine, scalars []Fr) (G2Affine, error)
+	// ntt transforms values in place, natural order in and out.
+	ntt func(values []Fr, inverse bool)
+}
+
+func (c *curve[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) generate(seed int64, size int) (*Vectors, error) {
+	rng := rand.New(rand.NewSource(seed))
+	modulus := c.id.ScalarField()
+	buf := make([]byte, len(PFr(new(Fr)).Marshal()))
+
+	d := &Decoded[Fr, G1Affine, G2Affine]{
+		Scalars: make([]Fr, size),
+		G1:      make([]G1Affine, size),
+		G2:      make([]G2Affine, size),
+	}
+	for i := 0; i < size; i++ {
+		s := new(big.Int).Rand(rng, modulus)
+		if err := PFr(&d.Scalars[i]).SetBytesCanonical(s.FillBytes(buf)); err != nil {
+			return nil, err
+		}
+
+		d.G1[i] = c.g1Base(new(big.Int).Rand(rng, modulus))
+		d.G2[i] = c.g2Base(new(big.Int).Rand(rng, modulus))
+	}
+
+	if err := c.expect(d); err != nil {
+		return nil, err
+	}
+
+	return &Vectors{
+		Version: Version,
+		Curve:   c.id.String(),
+		Seed:    seed,
+		Scalars: encode[Fr, PFr](d.Scalars),
+		G1:      encode[G1Affine, PG1](d.G1),
+		G2:      encode[G2Affine, PG2](d.G2),
+		MsmG1:   hex.EncodeToString(PG1(&d.MsmG1).Marshal()),
+		MsmG2:   hex.EncodeToString(PG2(&d.MsmG2).Marshal()),
+		Ntt:     encode[Fr, PFr](d.Ntt),
+		INtt:    encode[Fr, PFr](d.INtt),
+	}, nil
+}
+
+func (c *curve[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) validate(v *Vectors) error {
+	_, err := c.decode(v)
+	return err
+}
+
+// decode parses v and checks the expected outputs against the host.
+func (c *curve[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) decode(v *Vectors) (*Decoded[Fr, G1Affine, G2Affine], error) {
+	if v.Curve != c.id.String() {
+		return nil, fmt.Errorf("testvectors: %s vectors, want %s", v.Curve, c.id)
+	}
+	if len(v.Scalars) == 0 || len(v.G1) != len(v.Scalars) || len(v.G2) != len(v.Scalars) {
+		return nil, fmt.Errorf("testvectors: %d scalars, %d G1 and %d G2 points", len(v.Scalars), len(v.G1), len(v.G2))
+	}
+
+	var (
+		d   Decoded[Fr, G1Affine, G2Affine]
+		err error
+	)
+	if d.Scalars, err = decodeScalars[Fr, PFr]("scalars", v.Scalars); err != nil {
+		return nil, err
+	}
+	if d.G1, err = decodePoints[G1Affine, PG1]("g1", v.G1); err != nil {
+		return nil, err
+	}
+	if d.G2, err = decodePoints[G2Affine, PG2]("g2", v.G2); err != nil {
+		return nil, err
+	}
+
+	expected := d
+	if err := c.expect(&expected); err != nil {
+		return nil, err
+	}
+
+	if err := decodeExpected[G1Affine, PG1]("msm_g1", v.MsmG1, expected.MsmG1, &d.MsmG1); err != nil {
+		return nil, err
+	}
+	if err := decodeExpected[G2Affine, PG2]("msm_g2", v.MsmG2, expected.MsmG2, &d.MsmG2); err != nil {
+		return nil, err
+	}
+
+	if expected.Ntt == nil {
+		if v.Ntt != nil || v.INtt != nil {
+			return nil, fmt.Errorf("testvectors: ntt vectors for %d scalars, not a power of two", len(v.Scalars))
+		}
+
+		return &d, nil
+	}
+
+	if d.Ntt, err = decodeScalars[Fr, PFr]("ntt", v.Ntt); err != nil {
+		return nil, err
+	}
+	if d.INtt, err = decodeScalars[Fr, PFr]("intt", v.INtt); err != nil {
+		return nil, err
+	}
+	if !equal[Fr, PFr](d.Ntt, expected.Ntt) {
+		return nil, fmt.Errorf("testvectors: ntt does not match the host NTT")
+	}
+	if !equal[Fr, PFr](d.INtt, expected.INtt) {
+		return nil, fmt.Errorf("testvectors: intt does not match the host inverse NTT")
+	}
+
+	return &d, nil
+}
+
+// expect fills the MSM and NTT outputs of d from its inputs.
+func (c *curve[Fr, G1Affine, G2Affine, PFr, PG1, PG2]) expect(d *Decoded[Fr, G1Affine, G2Affine]) (err error) {
+	if d.MsmG1, err = c.msmG1(d.G1, d.Scalars); err != nil {
+		return err
+	}
+	if d.MsmG2, err = c.msmG2(d.G2, d.Scalars); err != nil {
+		return err
+	}
+
+	n := len(d.Scalars)
+	if n&(n-1) != 0 {
+		d.Ntt, d.INtt = nil, nil
+		return nil
+	}
+
+	d.Ntt = append([]Fr{}, d.Scalars...)
+	c.ntt(d.Ntt, false)
+	d.INtt = append([]Fr{}, d.Scalars...)
+	c.ntt(d.INtt, true)
+
+	return nil
+}
+
+func encode[T any, PT interface {
+	*T
+	Marshal() []byte
+}](values []T) []string {
+	if values == nil {
+		return nil
+	}
+
+	res := make([]string, len(values))
+	for i := range values {
+		res[i] = hex.EncodeToString(PT(&values[i]).Marshal())
+	}
+
+	return res
+}
+
+func decodeScalars[T any, PT scalar[T]](field string, hexes []string) ([]T, error) {
+	res := make([]T, len(hexes))
+	for i, h := range hexes {
+		b, err := hex.DecodeString(h)
+		if err == nil {
+			err = PT(&res[i]).SetBytesCanonical(b)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("testvectors: %s[%d]: %w", field, i, err)
+		}
+	}
+
+	return res, nil
+}
+
+func decodePoints[T any, PT point[T]](field string, hexes []string) ([]T, error) {
+	res := make([]T, len(hexes))
+	for i, h := range hexes {
+		b, err := hex.DecodeString(h)
+		if err == nil {
+			err = PT(&res[i]).Unmarshal(b)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("testvectors: %s[%d]: %w", field, i, err)
+		}
+	}
+
+	return res, nil
+}
+
+// decodeExpected decodes h into dst and checks it equals want.
+func decodeExpected[T any, PT point[T]](field, h string, want T, dst *T) error {
+	points, err := decodePoints[T, PT](field, []string{h})
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(PT(&points[0]).Marshal(), PT(&want).Marshal()) {
+		return fmt.Errorf("testvectors: %s does not match the host MSM", field)
+	}
+	*dst = points[0]
+
+	return nil
+}
+
+func equal[T any, PT interface {
+	*T
+	Marshal() []byte
+}](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(PT(&a[i]).Marshal(), PT(&b[i]).Marshal()) {
+			return false
+		}
+	}
+
+	return true
+}
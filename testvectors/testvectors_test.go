@@ -0,0 +1,113 @@
+package testvectors
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateDeterministic(t *testing.T) {
+	for _, id := range Curves() {
+		a, err := Generate(id, 7, 4)
+		assert.NoError(t, err, id)
+		b, err := Generate(id, 7, 4)
+		assert.NoError(t, err, id)
+		assert.Equal(t, a, b, id)
+
+		c, err := Generate(id, 8, 4)
+		assert.NoError(t, err, id)
+		assert.NotEqual(t, a.Scalars, c.Scalars, id)
+	}
+}
+
+func TestTestdataUpToDate(t *testing.T) {
+	for _, id := range Curves() {
+		path := filepath.Join("testdata", fmt.Sprintf("%s.json", id))
+		_, err := Load(path)
+		assert.NoError(t, err, path)
+
+		v, err := Generate(id, 1, 16)
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		assert.NoError(t, v.Write(&buf))
+
+		committed, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Equal(t, string(committed), buf.String(), "%s is stale, run go generate ./testvectors", path)
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	v, err := Generate(ecc.BLS12_381, 1, 8)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, v.Write(&buf))
+
+	read, err := Read(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, v, read)
+
+	d, err := DecodeBLS12381(read)
+	assert.NoError(t, err)
+	assert.Len(t, d.Scalars, 8)
+	assert.Len(t, d.Ntt, 8)
+}
+
+func TestNoNttForOddSizes(t *testing.T) {
+	v, err := Generate(ecc.BN254, 1, 5)
+	assert.NoError(t, err)
+	assert.Nil(t, v.Ntt)
+	assert.Nil(t, v.INtt)
+	assert.NoError(t, v.Validate())
+
+	v.Ntt = v.Scalars
+	assert.Error(t, v.Validate())
+}
+
+func TestValidateRejects(t *testing.T) {
+	fresh := func() *Vectors {
+		v, err := Generate(ecc.BN254, 1, 4)
+		assert.NoError(t, err)
+		return v
+	}
+
+	other, err := Generate(ecc.BN254, 2, 4)
+	assert.NoError(t, err)
+
+	for name, tamper := range map[string]func(v *Vectors){
+		"version":       func(v *Vectors) { v.Version = Version + 1 },
+		"unknown curve": func(v *Vectors) { v.Curve = "curve25519" },
+		"unsupported":   func(v *Vectors) { v.Curve = ecc.SECP256K1.String() },
+		"lengths":       func(v *Vectors) { v.G1 = v.G1[:3] },
+		"not hex":       func(v *Vectors) { v.Scalars[0] = "zz" },
+		"non canonical": func(v *Vectors) { v.Scalars[0] = strings.Repeat("ff", 32) },
+		"off curve":     func(v *Vectors) { v.G1[1] = v.G1[1][:len(v.G1[1])-2] + "00" },
+		"msm g1":        func(v *Vectors) { v.MsmG1 = other.MsmG1 },
+		"msm g2":        func(v *Vectors) { v.MsmG2 = other.MsmG2 },
+		"scalar":        func(v *Vectors) { v.Scalars[2] = other.Scalars[2] },
+		"ntt":           func(v *Vectors) { v.Ntt[0], v.Ntt[1] = v.Ntt[1], v.Ntt[0] },
+		"intt":          func(v *Vectors) { v.INtt = v.INtt[:2] },
+	} {
+		v := fresh()
+		assert.NoError(t, v.Validate(), name)
+
+		tamper(v)
+		assert.Error(t, v.Validate(), name)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	_, err := Generate(ecc.SECP256K1, 1, 4)
+	assert.Error(t, err)
+
+	_, err = Generate(ecc.BN254, 1, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,21 @@
+package iciclegnark
+
+import "sync/atomic"
+
+// skipPointValidation is inverted so that the zero value keeps the checks on.
+var skipPointValidation atomic.Bool
+
+// SetPointValidation turns on or off the host checks of the points every
+// curve package copies to the device. They are on by default: a point off
+// the curve or outside the prime order subgroup fails the copy with the
+// InvalidPointsError of its package. Only turn them off for points from a
+// trusted source, such as a proving key checked when it was read.
+func SetPointValidation(enabled bool) {
+	skipPointValidation.Store(!enabled)
+}
+
+// PointValidation reports whether the checks set with SetPointValidation are
+// on.
+func PointValidation() bool {
+	return !skipPointValidation.Load()
+}
@@ -0,0 +1,18 @@
+package iciclegnark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPointValidation(t *testing.T) {
+	assert.True(t, PointValidation())
+
+	SetPointValidation(false)
+	defer SetPointValidation(true)
+	assert.False(t, PointValidation())
+
+	SetPointValidation(true)
+	assert.True(t, PointValidation())
+}
@@ -0,0 +1,83 @@
+package iciclegnark
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+// Verification configures the host checks of device results. It is off by
+// default.
+//
+// When enabled, batched MSMs are checked with a random linear combination and
+// NTTs by evaluating input and output at a random point. A sampled fraction of
+// calls is also recomputed in full with gnark-crypto.
+type Verification struct {
+	Enabled bool
+	// SampleRate is the fraction of calls recomputed in full, from 0 to 1.
+	SampleRate float64
+}
+
+var (
+	verification atomic.Pointer[Verification]
+
+	samplerLock sync.Mutex
+	sampler     = rand.New(rand.NewSource(rand.Int63()))
+)
+
+// SetVerification sets the verification mode of every curve package.
+func SetVerification(v Verification) {
+	verification.Store(&v)
+}
+
+// CurrentVerification returns the mode set with SetVerification.
+func CurrentVerification() Verification {
+	if v := verification.Load(); v != nil {
+		return *v
+	}
+
+	return Verification{}
+}
+
+// Sample reports whether a call should be recomputed in full.
+func (v Verification) Sample() bool {
+	if !v.Enabled || v.SampleRate <= 0 {
+		return false
+	}
+	if v.SampleRate >= 1 {
+		return true
+	}
+
+	samplerLock.Lock()
+	defer samplerLock.Unlock()
+
+	return sampler.Float64() < v.SampleRate
+}
+
+// Checks run by the verification mode.
+const (
+	CheckRecompute         = "recompute"
+	CheckLinearCombination = "random linear combination"
+	CheckEvaluation        = "random evaluation"
+)
+
+// ErrMismatch is matched by every MismatchError.
+var ErrMismatch = errors.New("iciclegnark: device result does not match the host")
+
+// MismatchError reports a device result that failed a verification check.
+type MismatchError struct {
+	Curve ecc.ID
+	Op    string
+	Check string
+	Size  int
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("iciclegnark: %s %s of size %d failed the %s check", e.Curve, e.Op, e.Size, e.Check)
+}
+
+func (e *MismatchError) Is(target error) bool { return target == ErrMismatch }
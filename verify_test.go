@@ -0,0 +1,45 @@
+package iciclegnark
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerificationOffByDefault(t *testing.T) {
+	assert.Equal(t, Verification{}, CurrentVerification())
+	assert.False(t, CurrentVerification().Sample())
+}
+
+func TestSetVerification(t *testing.T) {
+	SetVerification(Verification{Enabled: true, SampleRate: 1})
+	defer SetVerification(Verification{})
+
+	assert.True(t, CurrentVerification().Enabled)
+	assert.True(t, CurrentVerification().Sample())
+
+	assert.False(t, Verification{Enabled: true}.Sample())
+	assert.False(t, Verification{SampleRate: 1}.Sample())
+
+	sampled := 0
+	v := Verification{Enabled: true, SampleRate: 0.25}
+	for i := 0; i < 4000; i++ {
+		if v.Sample() {
+			sampled++
+		}
+	}
+	assert.InDelta(t, 1000, sampled, 200)
+}
+
+func TestMismatchError(t *testing.T) {
+	var err error = &MismatchError{Curve: ecc.BN254, Op: "NttOnDevice", Check: CheckEvaluation, Size: 1024}
+	assert.True(t, errors.Is(fmt.Errorf("prove: %w", err), ErrMismatch))
+	assert.EqualError(t, err, "iciclegnark: bn254 NttOnDevice of size 1024 failed the random evaluation check")
+
+	var mismatch *MismatchError
+	assert.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, 1024, mismatch.Size)
+}